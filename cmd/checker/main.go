@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/config"
+	"documentation-mini-app/internal/domain/check"
+	"documentation-mini-app/internal/usecase/checkuc"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+)
+
+func writeText(w io.Writer, report *check.Report) error {
+	for _, issue := range report.Issues {
+		_, err := fmt.Fprintf(w, "%v\t%v #%v %q: %v\n",
+			issue.Kind, issue.EntityType, issue.EntityID, issue.EntityName, issue.Message)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "found %v issues\n", len(report.Issues))
+	return err
+}
+
+// Checker reports consistency problems of documentations. It exits with code 1 if any problem is found.
+func main() {
+	var configPath, format string
+	flag.StringVar(&configPath, "config-path", "configs/server_config.json", "path to config file")
+	flag.StringVar(&format, "format", "text", "output format: text or json")
+	flag.Parse()
+
+	dbURL := os.Getenv("DOC_DATABASE_URL")
+	if dbURL == "" {
+		log.Fatalln("Need DOC_DATABASE_URL env variable.")
+	}
+
+	conf := config.ParseFile(configPath)
+
+	ctx := context.TODO()
+
+	store, err := pgstore.New(ctx, dbURL)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	report, err := checkuc.New(store, conf.BaseURL).Check(ctx)
+	store.Close()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	switch format {
+	case "json":
+		err = json.NewEncoder(os.Stdout).Encode(report)
+	case "text":
+		err = writeText(os.Stdout, report)
+	default:
+		log.Fatalf("unknown format %q\n", format)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
@@ -3,19 +3,36 @@ package main
 import (
 	"context"
 	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/adapters/webhookhttp"
 	"documentation-mini-app/internal/config"
+	"documentation-mini-app/internal/i18n"
 	"documentation-mini-app/internal/ports/httpchi"
 	"documentation-mini-app/internal/usecase/appuc"
 	"documentation-mini-app/internal/usecase/articleuc"
+	"documentation-mini-app/internal/usecase/audituc"
+	"documentation-mini-app/internal/usecase/authuc"
+	"documentation-mini-app/internal/usecase/checkuc"
+	"documentation-mini-app/internal/usecase/commentuc"
 	"documentation-mini-app/internal/usecase/docuc"
 	"documentation-mini-app/internal/usecase/exampleuc"
+	"documentation-mini-app/internal/usecase/liveuc"
+	"documentation-mini-app/internal/usecase/overlapuc"
+	"documentation-mini-app/internal/usecase/reviewuc"
+	"documentation-mini-app/internal/usecase/taguc"
+	"documentation-mini-app/internal/usecase/translationuc"
+	"documentation-mini-app/internal/usecase/trashuc"
+	"documentation-mini-app/internal/usecase/webhookuc"
 	"documentation-mini-app/internal/views/htmlview"
+	"documentation-mini-app/static"
+	"documentation-mini-app/templates"
 	"flag"
 	"fmt"
 	"github.com/go-chi/chi/v5/middleware"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 
 	_ "github.com/jackc/pgx/v5"
@@ -23,22 +40,6 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
-func parseConfig(path string) *config.Config {
-	configFile, err := os.Open(path)
-	if err != nil {
-		log.Panicf("config file open: %v\n", err)
-	}
-
-	defer func(configFile *os.File) {
-		err := configFile.Close()
-		if err != nil {
-			log.Panicf("file close: %v\n", err)
-		}
-	}(configFile)
-
-	return config.Parse(configFile)
-}
-
 func main() {
 	var configPath string
 	flag.StringVar(&configPath, "config-path", "configs/server_config.json", "path to config file")
@@ -49,7 +50,7 @@ func main() {
 		log.Fatalln("Need DOC_DATABASE_URL env variable.")
 	}
 
-	conf := parseConfig(configPath)
+	conf := config.ParseFile(configPath)
 	fmt.Println(conf.Addr)
 
 	ctx := context.TODO()
@@ -60,95 +61,108 @@ func main() {
 	}
 	defer store.Close()
 
-	contentView, err := htmlview.New("templates/contents.html")
-	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
+	templateFS, staticFS := fs.FS(templates.FS), fs.FS(static.FS)
+	if conf.Dev {
+		templateFS = htmlview.Overlay(os.DirFS("templates"), templateFS)
 	}
-
-	crossedView, err := htmlview.New("templates/crossed.html")
-	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
+	if conf.OverrideDir != "" {
+		templateFS = htmlview.Overlay(os.DirFS(filepath.Join(conf.OverrideDir, "templates")), templateFS)
+		staticFS = htmlview.Overlay(os.DirFS(filepath.Join(conf.OverrideDir, "static")), staticFS)
 	}
 
-	getDocView, err := htmlview.New("templates/docs/get_doc.html")
+	bundle, err := i18n.New()
 	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
+		log.Panicf("messages load: %v\n", err)
 	}
 
-	createDocView, err := htmlview.New("templates/docs/create_doc.html")
-	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
+	var views *htmlview.Registry
+	if conf.Dev {
+		views = htmlview.NewDevRegistry(templateFS, bundle)
+	} else {
+		views, err = htmlview.NewRegistry(templateFS, bundle)
+		if err != nil {
+			log.Panicf("views create: %v\n", err)
+		}
 	}
 
-	editDocView, err := htmlview.New("templates/docs/edit_doc.html")
+	authUC, err := authuc.New(conf.Users, []byte(conf.SessionKey))
 	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
+		log.Panicf("auth create: %v\n", err)
 	}
 
-	deleteDocView, err := htmlview.New("templates/docs/delete_doc.html")
+	proxies, err := httpchi.ParseProxies(conf.TrustedProxies)
 	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
+		log.Panicf("config: %v\n", err)
 	}
 
-	getArticleView, err := htmlview.New("templates/articles/get_article.html")
-	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
-	}
+	authHandler := httpchi.NewAuthHandler(authUC, views, proxies)
 
-	createArticleView, err := htmlview.New("templates/articles/create_article.html")
-	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
-	}
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.Logger)
+	r.Use(authHandler.UserMiddleware)
+	r.Use(httpchi.LocaleMiddleware(bundle))
+	r.Use(httpchi.AuditMiddleware)
 
-	editArticleView, err := htmlview.New("templates/articles/edit_article.html")
-	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
-	}
+	docUC := docuc.New(store)
+	docHandler := httpchi.NewDocHandler(docUC, views)
 
-	deleteArticleView, err := htmlview.New("templates/articles/delete_article.html")
-	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
-	}
+	artUC := articleuc.New(store)
+	artHandler := httpchi.NewArticleHandler(artUC, views)
 
-	getExampleView, err := htmlview.New("templates/examples/get_example.html")
+	exaUC := exampleuc.New(store)
+	err = exaUC.FillCodeHashes(ctx)
 	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
+		log.Panicf("fill code hashes: %v\n", err)
 	}
 
-	createExampleView, err := htmlview.New("templates/examples/create_example.html")
-	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
-	}
+	exaHandler := httpchi.NewExampleHandler(exaUC, views)
 
-	editExampleView, err := htmlview.New("templates/examples/edit_example.html")
-	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
-	}
+	reviewUC := reviewuc.New(store)
+	reviewHandler := httpchi.NewReviewHandler(reviewUC, views)
 
-	deleteExampleView, err := htmlview.New("templates/examples/delete_example.html")
-	if err != nil {
-		log.Panicf("contentView create: %v\n", err)
-	}
+	commentUC := commentuc.New(store)
+	commentHandler := httpchi.NewCommentHandler(commentUC, views)
 
-	r := chi.NewRouter()
-	r.Use(middleware.Logger)
+	tagUC := taguc.New(store)
+	tagHandler := httpchi.NewTagHandler(tagUC, views)
 
-	docUC := docuc.New(store)
-	docHandler := httpchi.NewDocHandler(docUC,
-		getDocView, createDocView, editDocView, deleteDocView)
+	trashUC := trashuc.New(store, time.Duration(conf.TrashRetentionDays)*24*time.Hour)
+	trashHandler := httpchi.NewTrashHandler(trashUC, views)
+	go trashUC.RunPurge(ctx, time.Hour)
 
-	artUC := articleuc.New(store)
-	artHandler := httpchi.NewArticleHandler(artUC,
-		getArticleView, createArticleView, editArticleView, deleteArticleView)
+	webhookUC := webhookuc.New(store, webhookhttp.New(10*time.Second))
+	webhookHandler := httpchi.NewWebhookHandler(webhookUC, views)
+	go webhookUC.RunDelivery(ctx, 5*time.Second)
 
-	exaUC := exampleuc.New(store)
-	exaHandler := httpchi.NewExampleHandler(exaUC,
-		getExampleView, createExampleView, editExampleView, deleteExampleView)
+	liveUC := liveuc.New(store)
+	liveHandler := httpchi.NewLiveHandler(liveUC)
+	go liveUC.Run(ctx, 5*time.Second)
+
+	auditUC := audituc.New(store)
+	auditHandler := httpchi.NewAuditHandler(auditUC, views)
+
+	checkUC := checkuc.New(store, conf.BaseURL)
+	checkHandler := httpchi.NewCheckHandler(checkUC, views)
+
+	overlapUC := overlapuc.New(store)
+	overlapHandler := httpchi.NewOverlapHandler(overlapUC, views)
+
+	translationUC := translationuc.New(store)
+	translationHandler := httpchi.NewTranslationHandler(translationUC, views)
+
+	staticHandler := httpchi.NewStaticHandler(staticFS)
 
 	appUC := appuc.New(store)
-	appHandler := httpchi.NewAppHandler(r, appUC,
-		artHandler, docHandler, exaHandler,
-		contentView, crossedView)
+	err = appUC.FillSlugs(ctx)
+	if err != nil {
+		log.Panicf("fill slugs: %v\n", err)
+	}
+
+	appHandler := httpchi.NewAppHandler(r, appUC, views,
+		authHandler, artHandler, docHandler, exaHandler, reviewHandler, commentHandler, tagHandler,
+		trashHandler, auditHandler, webhookHandler, liveHandler, checkHandler, overlapHandler,
+		translationHandler, staticHandler)
 
 	server := http.Server{
 		Addr:         conf.Addr,
@@ -3,35 +3,41 @@ package pgstore
 import (
 	"context"
 	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/trash"
 	"errors"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 	"log"
 )
 
 type ArticleRepoPG struct {
-	db *pgxpool.Pool
+	db dbtx
 	s  *Store
 }
 
-func NewArticleRepoPG(db *pgxpool.Pool, s *Store) *ArticleRepoPG {
+func NewArticleRepoPG(db dbtx, s *Store) *ArticleRepoPG {
 	return &ArticleRepoPG{db: db, s: s}
 }
 
 func (r *ArticleRepoPG) Create(ctx context.Context, art *article.Article) error {
-	q := "insert into article(name, description) values($1, $2) returning id"
+	q := "insert into article(name, description, published) values($1, $2, $3) returning id, version"
 
 	var artID int
-	err := r.db.QueryRow(ctx, q, art.Name, art.Description).Scan(&artID)
+	err := r.db.QueryRow(ctx, q, art.Name, art.Description, art.Published).Scan(&artID, &art.Version)
 	art.ID = artID
 
 	return err
 }
 
 func (r *ArticleRepoPG) GetByID(ctx context.Context, id int) (*article.Article, error) {
-	q := `SELECT a.id, a.name, a.description FROM article a WHERE id = $1`
+	q := `SELECT a.id, a.name, coalesce(a.slug, ''), a.description, a.published, a.version FROM article a
+			WHERE id = $1 and a.deleted_at is null`
 
 	var art article.Article
-	err := r.db.QueryRow(ctx, q, id).Scan(&art.ID, &art.Name, &art.Description)
+	err := r.db.QueryRow(ctx, q, id).Scan(&art.ID, &art.Name, &art.Slug, &art.Description, &art.Published,
+		&art.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, article.ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -41,33 +47,50 @@ func (r *ArticleRepoPG) GetByID(ctx context.Context, id int) (*article.Article,
 		return nil, err
 	}
 
+	art.Tags, err = r.GetTags(ctx, art.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	art.Links, err = r.GetLinks(ctx, art.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	art.Backlinks, err = r.GetBacklinks(ctx, art.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &art, nil
 }
 
-func (r *ArticleRepoPG) GetAllNames(ctx context.Context) ([]string, error) {
-	q := "SELECT a.name FROM article a ORDER BY a.name"
+func (r *ArticleRepoPG) GetAll(ctx context.Context) ([]article.Article, error) {
+	q := `SELECT a.id, a.name, coalesce(a.slug, ''), a.description, a.published FROM article a
+			WHERE a.deleted_at is null ORDER BY a.id`
 
 	rows, err := r.db.Query(ctx, q)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	res := make([]string, 0)
+	res := make([]article.Article, 0)
 	for rows.Next() {
-		var name string
-		err = rows.Scan(&name)
+		art := article.Article{}
+		err = rows.Scan(&art.ID, &art.Name, &art.Slug, &art.Description, &art.Published)
 		if err != nil {
 			return nil, err
 		}
-		res = append(res, name)
+		res = append(res, art)
 	}
 
 	return res, nil
 }
 
 func (r *ArticleRepoPG) GetByDocID(ctx context.Context, docID int) ([]article.Article, error) {
-	q := `SELECT a.id, a.name, a.description FROM documentation_articles da 
-			JOIN article a on a.id = da.article_id WHERE documentation_id = $1`
+	q := `SELECT a.id, a.name, coalesce(a.slug, ''), a.description, a.published FROM documentation_articles da 
+			JOIN article a on a.id = da.article_id WHERE documentation_id = $1 and a.deleted_at is null`
 
 	rows, err := r.db.Query(ctx, q, docID)
 	if err != nil {
@@ -78,7 +101,7 @@ func (r *ArticleRepoPG) GetByDocID(ctx context.Context, docID int) ([]article.Ar
 	res := make([]article.Article, 0)
 	for rows.Next() {
 		art := article.Article{}
-		err = rows.Scan(&art.ID, &art.Name, &art.Description)
+		err = rows.Scan(&art.ID, &art.Name, &art.Slug, &art.Description, &art.Published)
 		if err != nil {
 			return nil, err
 		}
@@ -94,31 +117,116 @@ func (r *ArticleRepoPG) AddToDoc(ctx context.Context, artID int, docID int) erro
 	return err
 }
 
+// LockVersion returns current version of article and locks it until the end of transaction,
+// so the article can't be published while the version is in use.
+func (r *ArticleRepoPG) LockVersion(ctx context.Context, id int) (int, error) {
+	q := "select a.version from article a where a.id = $1 and a.deleted_at is null for update"
+
+	var version int
+	err := r.db.QueryRow(ctx, q, id).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, article.ErrNotFound
+	}
+
+	return version, err
+}
+
+// Update saves article if it is still of art.Version and increments its version.
+// It returns article.ErrConflict when article was updated by someone else.
 func (r *ArticleRepoPG) Update(ctx context.Context, art *article.Article) error {
-	q := "update article a set name = $1, description = $2 where a.id = $3"
+	q := "update article a set name = $1, description = $2, version = version + 1 where a.id = $3 and a.version = $4"
 
-	commandTag, err := r.db.Exec(ctx, q, art.Name, art.Description, art.ID)
+	commandTag, err := r.db.Exec(ctx, q, art.Name, art.Description, art.ID, art.Version)
 	if err != nil {
 		return err
 	}
 
 	if commandTag.RowsAffected() != 1 {
 		log.Printf("update article rows affected equals %v\n", commandTag.RowsAffected())
-		return errors.New("update article rows affected not equals 1")
+		return article.ErrConflict
 	}
+	art.Version++
 
 	return nil
 }
 
+// Delete moves article to trash. It keeps all relations of article to restore it later.
 func (r *ArticleRepoPG) Delete(ctx context.Context, artID int) error {
+	q := "update article t set deleted_at = now() where t.id = $1 and t.deleted_at is null"
+	commandTag, err := r.db.Exec(ctx, q, artID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		log.Printf("delete article rows affected equals %v\n", commandTag.RowsAffected())
+		return errors.New("article already deleted")
+	}
+
+	return nil
+}
+
+// Restore returns article from trash.
+func (r *ArticleRepoPG) Restore(ctx context.Context, artID int) error {
+	q := `update article t set deleted_at = null, deleted_with_documentation_id = null
+			where t.id = $1 and t.deleted_at is not null`
+	commandTag, err := r.db.Exec(ctx, q, artID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return trash.ErrNotInTrash
+	}
+
+	return nil
+}
+
+// Purge deletes article with all its relations permanently.
+func (r *ArticleRepoPG) Purge(ctx context.Context, artID int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
 	q := "delete from documentation_articles where article_id=$1"
-	_, err := r.db.Exec(ctx, q, artID)
+	_, err = tx.Exec(ctx, q, artID)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from article_examples where article_id=$1"
+	_, err = tx.Exec(ctx, q, artID)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from article_link where article_id=$1"
+	_, err = tx.Exec(ctx, q, artID)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from article_tags where article_id=$1"
+	_, err = tx.Exec(ctx, q, artID)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from article_translation where article_id=$1"
+	_, err = tx.Exec(ctx, q, artID)
+	if err != nil {
+		return err
+	}
+
+	err = deleteSlugRedirects(ctx, tx, slugTableArticle, artID)
 	if err != nil {
 		return err
 	}
 
 	q = "delete from article where id=$1"
-	commandTag, err := r.db.Exec(ctx, q, artID)
+	commandTag, err := tx.Exec(ctx, q, artID)
 	if err != nil {
 		return err
 	}
@@ -128,15 +236,17 @@ func (r *ArticleRepoPG) Delete(ctx context.Context, artID int) error {
 		return errors.New("article already deleted")
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
 
 func (r *ArticleRepoPG) GetWithoutDoc(ctx context.Context) ([]article.Article, error) {
 	q := `
-	select a.id, a.name, a.description from article a 
-    left join documentation_articles da ON da.article_id = a.id
-    where da.documentation_id is null
-    `
+	select a.id, a.name, coalesce(a.slug, ''), a.description, a.published from article a
+	where a.deleted_at is null and not exists(
+		select 1 from documentation_articles da
+		join documentation d on d.id = da.documentation_id
+		where da.article_id = a.id and d.deleted_at is null)
+	`
 
 	rows, err := r.db.Query(ctx, q)
 	if err != nil {
@@ -147,7 +257,262 @@ func (r *ArticleRepoPG) GetWithoutDoc(ctx context.Context) ([]article.Article, e
 	res := make([]article.Article, 0)
 	for rows.Next() {
 		art := article.Article{}
-		err = rows.Scan(&art.ID, &art.Name, &art.Description)
+		err = rows.Scan(&art.ID, &art.Name, &art.Slug, &art.Description, &art.Published)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, art)
+	}
+
+	return res, nil
+}
+
+// SetLinks replaces outgoing references of article.
+func (r *ArticleRepoPG) SetLinks(ctx context.Context, artID int, links []article.Link) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	_, err = tx.Exec(ctx, "delete from article_link where article_id=$1", artID)
+	if err != nil {
+		return err
+	}
+
+	q := "insert into article_link(article_id, target_doc, target_name, position) values($1, $2, $3, $4)"
+	for i, l := range links {
+		_, err = tx.Exec(ctx, q, artID, l.DocName, l.ArticleName, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// linkTarget selects id of article that reference l resolves to: the published article out of trash
+// with lowest id that has the referenced name and belongs to the referenced documentation, if it is set.
+const linkTarget = `
+	select t.id from article t
+	where t.name = l.target_name and t.published and t.deleted_at is null and (l.target_doc = '' or exists(
+		select 1 from documentation_articles da
+		join documentation d on d.id = da.documentation_id
+		where da.article_id = t.id and d.name = l.target_doc and d.deleted_at is null))
+	order by t.id limit 1`
+
+// GetLinks returns outgoing references of article resolved by linkTarget. ArticleID of link is zero
+// if reference is not resolved.
+func (r *ArticleRepoPG) GetLinks(ctx context.Context, artID int) ([]article.Link, error) {
+	q := `select l.target_doc, l.target_name, coalesce((` + linkTarget + `), 0)
+			from article_link l where l.article_id = $1 order by l.position`
+
+	rows, err := r.db.Query(ctx, q, artID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]article.Link, 0)
+	for rows.Next() {
+		l := article.Link{}
+		err = rows.Scan(&l.DocName, &l.ArticleName, &l.ArticleID)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, l)
+	}
+
+	return res, nil
+}
+
+// ResolveLinks returns links with ArticleID resolved by linkTarget, e.g. links of translated text which
+// are not stored. ArticleID of link is zero if reference is not resolved.
+func (r *ArticleRepoPG) ResolveLinks(ctx context.Context, links []article.Link) ([]article.Link, error) {
+	docs := make([]string, len(links))
+	names := make([]string, len(links))
+	for i, l := range links {
+		docs[i], names[i] = l.DocName, l.ArticleName
+	}
+
+	q := `select l.target_doc, l.target_name, coalesce((` + linkTarget + `), 0)
+			from unnest($1::text[], $2::text[]) with ordinality as l(target_doc, target_name, position)
+			order by l.position`
+
+	rows, err := r.db.Query(ctx, q, docs, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]article.Link, 0, len(links))
+	for rows.Next() {
+		l := article.Link{}
+		err = rows.Scan(&l.DocName, &l.ArticleName, &l.ArticleID)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, l)
+	}
+
+	return res, rows.Err()
+}
+
+// GetBacklinks returns articles with references that resolve to article by linkTarget.
+func (r *ArticleRepoPG) GetBacklinks(ctx context.Context, artID int) ([]article.Article, error) {
+	q := `select distinct a.id, a.name, a.description, a.published from article_link l
+			join article a on a.id = l.article_id
+			where l.target_name = (select name from article where id = $1) and a.id != $1 and a.deleted_at is null
+				and (` + linkTarget + `) = $1
+			order by a.name, a.id`
+
+	rows, err := r.db.Query(ctx, q, artID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]article.Article, 0)
+	for rows.Next() {
+		art := article.Article{}
+		err = rows.Scan(&art.ID, &art.Name, &art.Description, &art.Published)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, art)
+	}
+
+	return res, nil
+}
+
+func (r *ArticleRepoPG) SetPublished(ctx context.Context, artID int, published bool) error {
+	q := "update article a set published = $1 where a.id = $2"
+	_, err := r.db.Exec(ctx, q, published, artID)
+	return err
+}
+
+// SetTags replaces tags of article.
+func (r *ArticleRepoPG) SetTags(ctx context.Context, artID int, tags []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	_, err = tx.Exec(ctx, "delete from article_tags where article_id=$1", artID)
+	if err != nil {
+		return err
+	}
+
+	q := "insert into article_tags(article_id, tag) values($1, $2) on conflict do nothing"
+	for _, t := range tags {
+		_, err = tx.Exec(ctx, q, artID, t)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetTags returns tags of article ordered by name.
+func (r *ArticleRepoPG) GetTags(ctx context.Context, artID int) ([]string, error) {
+	return queryStrings(ctx, r.db, "select t.tag from article_tags t where t.article_id = $1 order by t.tag", artID)
+}
+
+// GetIDsByTag returns set of ids of articles marked by tag.
+func (r *ArticleRepoPG) GetIDsByTag(ctx context.Context, tag string) (map[int]bool, error) {
+	rows, err := r.db.Query(ctx, "select t.article_id from article_tags t where t.tag = $1", tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		res[id] = true
+	}
+
+	return res, nil
+}
+
+// Search returns articles whose name or description contains query and that are marked by tag.
+// Empty query or tag matches any article.
+func (r *ArticleRepoPG) Search(ctx context.Context, query string, tag string) ([]article.Article, error) {
+	q := `
+	select a.id, a.name, a.description, a.published from article a
+	where a.deleted_at is null
+	and (a.name ilike '%' || $1 || '%' escape '\' or a.description ilike '%' || $1 || '%' escape '\')
+	and ($2 = '' or exists(select 1 from article_tags t where t.article_id = a.id and t.tag = $2))
+	order by a.name, a.id
+	`
+
+	rows, err := r.db.Query(ctx, q, escapeLike(query), tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]article.Article, 0)
+	for rows.Next() {
+		art := article.Article{}
+		err = rows.Scan(&art.ID, &art.Name, &art.Description, &art.Published)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, art)
+	}
+
+	return res, nil
+}
+
+// SetSlug makes slug of article from its name. Previous slug leads to article after change.
+func (r *ArticleRepoPG) SetSlug(ctx context.Context, artID int, name string) error {
+	return setSlug(ctx, r.db, slugTableArticle, artID, name)
+}
+
+// GetIDBySlug returns id and current slug of article that has slug s now or had it before.
+func (r *ArticleRepoPG) GetIDBySlug(ctx context.Context, s string) (int, string, error) {
+	id, cur, err := getIDBySlug(ctx, r.db, slugTableArticle, s)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", article.ErrNotFound
+	}
+
+	return id, cur, err
+}
+
+// GetDocSlugs returns slugs of documentations that contain article, ordered by documentation id.
+func (r *ArticleRepoPG) GetDocSlugs(ctx context.Context, artID int) ([]string, error) {
+	q := `
+	select d.slug from documentation_articles da
+	join documentation d on d.id = da.documentation_id
+	where da.article_id = $1 and d.slug is not null and d.deleted_at is null
+	order by d.id
+	`
+
+	return queryStrings(ctx, r.db, q, artID)
+}
+
+// GetExclusiveByDocID returns articles of documentation that belong to no other documentation.
+func (r *ArticleRepoPG) GetExclusiveByDocID(ctx context.Context, docID int) ([]article.Article, error) {
+	q := `SELECT a.id, a.name, coalesce(a.slug, ''), a.description, a.published FROM article a
+			WHERE a.id in (` + exclusiveArticleIDs + `) ORDER BY a.name, a.id`
+
+	rows, err := r.db.Query(ctx, q, docID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]article.Article, 0)
+	for rows.Next() {
+		art := article.Article{}
+		err = rows.Scan(&art.ID, &art.Name, &art.Slug, &art.Description, &art.Published)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,114 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/doc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArticleRepoPG_LinksSharedName(t *testing.T) {
+	ctx := context.TODO()
+
+	s, truncate := TestStore(ctx, t, dbURL)
+	defer truncate(ctx, "documentation", "article")
+
+	goDoc, rustDoc := &doc.Documentation{Name: "Go"}, &doc.Documentation{Name: "Rust"}
+	for _, d := range []*doc.Documentation{goDoc, rustDoc} {
+		require.NoError(t, s.Doc().Create(ctx, d))
+	}
+
+	// rustIntro has lower id, so unqualified reference resolves to it.
+	rustIntro, goIntro := &article.Article{Name: "Intro", Published: true}, &article.Article{Name: "Intro", Published: true}
+	plain := &article.Article{Name: "plain", Description: "see [[Intro]]", Published: true}
+	qualified := &article.Article{Name: "qualified", Description: "see [[Go/Intro]]", Published: true}
+	for _, a := range []*article.Article{rustIntro, goIntro, plain, qualified} {
+		require.NoError(t, s.Article().Create(ctx, a))
+		require.NoError(t, s.Article().SetLinks(ctx, a.ID, article.ParseLinks(a.Description)))
+	}
+	require.NoError(t, s.Article().AddToDoc(ctx, rustIntro.ID, rustDoc.ID))
+	require.NoError(t, s.Article().AddToDoc(ctx, goIntro.ID, goDoc.ID))
+
+	links, err := s.Article().GetLinks(ctx, plain.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []article.Link{{ArticleName: "Intro", ArticleID: rustIntro.ID}}, links)
+
+	links, err = s.Article().GetLinks(ctx, qualified.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []article.Link{{DocName: "Go", ArticleName: "Intro", ArticleID: goIntro.ID}}, links)
+
+	backlinks, err := s.Article().GetBacklinks(ctx, rustIntro.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{plain.ID}, articleIDs(backlinks))
+
+	backlinks, err = s.Article().GetBacklinks(ctx, goIntro.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{qualified.ID}, articleIDs(backlinks))
+}
+
+func TestArticleRepoPG_LinksUnpublished(t *testing.T) {
+	ctx := context.TODO()
+
+	s, truncate := TestStore(ctx, t, dbURL)
+	defer truncate(ctx, "article")
+
+	draft := &article.Article{Name: "Intro"}
+	src := &article.Article{Name: "src", Description: "see [[Intro]]", Published: true}
+	for _, a := range []*article.Article{draft, src} {
+		require.NoError(t, s.Article().Create(ctx, a))
+	}
+	require.NoError(t, s.Article().SetLinks(ctx, src.ID, article.ParseLinks(src.Description)))
+
+	links, err := s.Article().GetLinks(ctx, src.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []article.Link{{ArticleName: "Intro"}}, links)
+
+	backlinks, err := s.Article().GetBacklinks(ctx, draft.ID)
+	require.NoError(t, err)
+	assert.Empty(t, backlinks)
+
+	// Reference skips the draft with lower id and resolves to the published article with the same name.
+	published := &article.Article{Name: "Intro", Published: true}
+	require.NoError(t, s.Article().Create(ctx, published))
+
+	links, err = s.Article().GetLinks(ctx, src.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []article.Link{{ArticleName: "Intro", ArticleID: published.ID}}, links)
+
+	backlinks, err = s.Article().GetBacklinks(ctx, published.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int{src.ID}, articleIDs(backlinks))
+
+	require.NoError(t, s.Article().Delete(ctx, published.ID))
+
+	links, err = s.Article().GetLinks(ctx, src.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []article.Link{{ArticleName: "Intro"}}, links)
+}
+
+func TestArticleRepoPG_ResolveLinks(t *testing.T) {
+	ctx := context.TODO()
+
+	s, truncate := TestStore(ctx, t, dbURL)
+	defer truncate(ctx, "article")
+
+	draft, published := &article.Article{Name: "Draft"}, &article.Article{Name: "Intro", Published: true}
+	for _, a := range []*article.Article{draft, published} {
+		require.NoError(t, s.Article().Create(ctx, a))
+	}
+
+	links, err := s.Article().ResolveLinks(ctx, article.ParseLinks("[[Intro]], [[Draft]] and [[Go/Intro]]"))
+	require.NoError(t, err)
+	assert.Equal(t, []article.Link{
+		{ArticleName: "Intro", ArticleID: published.ID},
+		{ArticleName: "Draft"},
+		{DocName: "Go", ArticleName: "Intro"},
+	}, links)
+
+	links, err = s.Article().ResolveLinks(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, links)
+}
@@ -0,0 +1,103 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/audit"
+	"fmt"
+	"strings"
+)
+
+type AuditRepoPG struct {
+	db dbtx
+}
+
+func NewAuditRepoPG(db dbtx) *AuditRepoPG {
+	return &AuditRepoPG{db: db}
+}
+
+// Record appends entry about change of entity made in ctx. Before and after are states of entity
+// marshaled to JSON, nil means no state.
+func (r *AuditRepoPG) Record(ctx context.Context, action, entityType string, entityID int, before, after any) error {
+	e, err := audit.New(ctx, action, entityType, entityID, before, after)
+	if err != nil {
+		return fmt.Errorf("audit entry: %w", err)
+	}
+
+	q := `insert into audit_log(actor, action, entity_type, entity_id, before, after, request_id, ip)
+			values($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err = r.db.Exec(ctx, q, e.Actor, e.Action, e.EntityType, e.EntityID, jsonOrNil(e.Before), jsonOrNil(e.After),
+		e.RequestID, e.IP)
+
+	return err
+}
+
+// Find returns entries matched by filter, recent first.
+func (r *AuditRepoPG) Find(ctx context.Context, f audit.Filter) ([]audit.Entry, error) {
+	conds := make([]string, 0)
+	args := make([]any, 0)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if f.Actor != "" {
+		add("l.actor = $%d", f.Actor)
+	}
+	if f.Action != "" {
+		add("l.action = $%d", f.Action)
+	}
+	if f.EntityType != "" {
+		add("l.entity_type = $%d", f.EntityType)
+	}
+	if f.EntityID != 0 {
+		add("l.entity_id = $%d", f.EntityID)
+	}
+	if !f.From.IsZero() {
+		add("l.created_at >= $%d", f.From)
+	}
+	if !f.To.IsZero() {
+		add("l.created_at < $%d", f.To)
+	}
+
+	q := `select l.id, l.created_at, l.actor, l.action, l.entity_type, l.entity_id, l.before, l.after,
+			l.request_id, l.ip from audit_log l`
+	if len(conds) > 0 {
+		q += " where " + strings.Join(conds, " and ")
+	}
+	q += " order by l.id desc"
+	if f.Limit > 0 {
+		q += fmt.Sprintf(" limit %d", f.Limit)
+	}
+
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]audit.Entry, 0)
+	for rows.Next() {
+		var e audit.Entry
+		var before, after []byte
+		err = rows.Scan(&e.ID, &e.CreatedAt, &e.Actor, &e.Action, &e.EntityType, &e.EntityID, &before, &after,
+			&e.RequestID, &e.IP)
+		if err != nil {
+			return nil, err
+		}
+		e.Before = before
+		e.After = after
+
+		res = append(res, e)
+	}
+
+	return res, rows.Err()
+}
+
+// jsonOrNil returns nil for empty JSON to store SQL null.
+func jsonOrNil(b []byte) any {
+	if len(b) == 0 {
+		return nil
+	}
+
+	return string(b)
+}
@@ -0,0 +1,196 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/comment"
+	"errors"
+	"log"
+)
+
+type CommentRepoPG struct {
+	db dbtx
+}
+
+func NewCommentRepoPG(db dbtx) *CommentRepoPG {
+	return &CommentRepoPG{db: db}
+}
+
+// CreateThread creates thread with its first comment.
+func (r *CommentRepoPG) CreateThread(ctx context.Context, t *comment.Thread, first *comment.Comment) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	var exaID *int
+	if t.ExampleID != 0 {
+		exaID = &t.ExampleID
+	}
+
+	q := `insert into comment_thread(article_id, example_id, author) values($1, $2, $3) returning id, created_at`
+	err = tx.QueryRow(ctx, q, t.ArticleID, exaID, t.Author).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	q = `insert into comment(thread_id, author, body) values($1, $2, $3) returning id, created_at`
+	err = tx.QueryRow(ctx, q, t.ID, first.Author, first.Body).Scan(&first.ID, &first.CreatedAt)
+	if err != nil {
+		return err
+	}
+	first.ThreadID = t.ID
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+	t.Comments = []comment.Comment{*first}
+
+	return nil
+}
+
+func (r *CommentRepoPG) AddComment(ctx context.Context, c *comment.Comment) error {
+	q := `insert into comment(thread_id, author, body) values($1, $2, $3) returning id, created_at`
+	return r.db.QueryRow(ctx, q, c.ThreadID, c.Author, c.Body).Scan(&c.ID, &c.CreatedAt)
+}
+
+func (r *CommentRepoPG) SetResolved(ctx context.Context, threadID int, resolved bool, by string) error {
+	q := "update comment_thread t set resolved = $1, resolved_by = $2 where t.id = $3"
+
+	commandTag, err := r.db.Exec(ctx, q, resolved, by, threadID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		log.Printf("update thread rows affected equals %v\n", commandTag.RowsAffected())
+		return errors.New("update thread rows affected not equals 1")
+	}
+
+	return nil
+}
+
+const threadColumns = `t.id, t.article_id, coalesce(t.example_id, 0), t.resolved, t.resolved_by, t.author, t.created_at,
+	a.name, coalesce(e.name, '')`
+
+func (r *CommentRepoPG) GetThread(ctx context.Context, threadID int) (*comment.Thread, error) {
+	q := `select ` + threadColumns + ` from comment_thread t
+			join article a on a.id = t.article_id
+			left join example e on e.id = t.example_id
+			where t.id = $1`
+
+	var t comment.Thread
+	err := r.db.QueryRow(ctx, q, threadID).Scan(&t.ID, &t.ArticleID, &t.ExampleID, &t.Resolved, &t.ResolvedBy,
+		&t.Author, &t.CreatedAt, &t.ArticleName, &t.ExampleName)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Comments, err = r.GetComments(ctx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// GetByArticleID returns all threads of article and its examples with comments.
+func (r *CommentRepoPG) GetByArticleID(ctx context.Context, artID int) ([]comment.Thread, error) {
+	q := `select ` + threadColumns + ` from comment_thread t
+			join article a on a.id = t.article_id
+			left join example e on e.id = t.example_id
+			where t.article_id = $1 order by t.created_at, t.id`
+
+	return r.queryThreads(ctx, q, artID)
+}
+
+// GetOpenByDocID returns unresolved threads of all articles of documentation with comments.
+func (r *CommentRepoPG) GetOpenByDocID(ctx context.Context, docID int) ([]comment.Thread, error) {
+	q := `select ` + threadColumns + ` from comment_thread t
+			join article a on a.id = t.article_id
+			join documentation_articles da on da.article_id = t.article_id
+			left join example e on e.id = t.example_id
+			where da.documentation_id = $1 and not t.resolved and a.deleted_at is null
+			order by a.name, t.created_at, t.id`
+
+	return r.queryThreads(ctx, q, docID)
+}
+
+func (r *CommentRepoPG) queryThreads(ctx context.Context, q string, args ...any) ([]comment.Thread, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]comment.Thread, 0)
+	for rows.Next() {
+		t := comment.Thread{}
+		err = rows.Scan(&t.ID, &t.ArticleID, &t.ExampleID, &t.Resolved, &t.ResolvedBy, &t.Author, &t.CreatedAt,
+			&t.ArticleName, &t.ExampleName)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+	rows.Close()
+
+	for i := range res {
+		res[i].Comments, err = r.GetComments(ctx, res[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func (r *CommentRepoPG) GetComments(ctx context.Context, threadID int) ([]comment.Comment, error) {
+	q := `select c.id, c.thread_id, c.author, c.body, c.created_at from comment c
+			where c.thread_id = $1 order by c.created_at, c.id`
+
+	rows, err := r.db.Query(ctx, q, threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]comment.Comment, 0)
+	for rows.Next() {
+		c := comment.Comment{}
+		err = rows.Scan(&c.ID, &c.ThreadID, &c.Author, &c.Body, &c.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+
+	return res, nil
+}
+
+// DeleteByArticleID deletes threads of article with their comments.
+func (r *CommentRepoPG) DeleteByArticleID(ctx context.Context, artID int) error {
+	q := "delete from comment where thread_id in (select id from comment_thread where article_id = $1)"
+	_, err := r.db.Exec(ctx, q, artID)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from comment_thread where article_id = $1"
+	_, err = r.db.Exec(ctx, q, artID)
+	return err
+}
+
+// DeleteByExampleID deletes threads of example with their comments.
+func (r *CommentRepoPG) DeleteByExampleID(ctx context.Context, exaID int) error {
+	q := "delete from comment where thread_id in (select id from comment_thread where example_id = $1)"
+	_, err := r.db.Exec(ctx, q, exaID)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from comment_thread where example_id = $1"
+	_, err = r.db.Exec(ctx, q, exaID)
+	return err
+}
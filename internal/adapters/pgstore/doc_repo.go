@@ -3,25 +3,26 @@ package pgstore
 import (
 	"context"
 	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/trash"
 	"errors"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 	"log"
 )
 
 type DocRepoPG struct {
-	db *pgxpool.Pool
+	db dbtx
 	s  *Store
 }
 
-func NewDocRepoPG(db *pgxpool.Pool, s *Store) *DocRepoPG {
+func NewDocRepoPG(db dbtx, s *Store) *DocRepoPG {
 	return &DocRepoPG{db: db, s: s}
 }
 
 func (r *DocRepoPG) Create(ctx context.Context, d *doc.Documentation) error {
-	q := "insert into documentation(name, default_highlight_language) values($1, $2) returning id"
+	q := "insert into documentation(name, default_highlight_language) values($1, $2) returning id, version"
 
 	var docID int
-	err := r.db.QueryRow(ctx, q, d.Name, d.DefaultHighlightLanguage).Scan(&docID)
+	err := r.db.QueryRow(ctx, q, d.Name, d.DefaultHighlightLanguage).Scan(&docID, &d.Version)
 	d.ID = docID
 
 	if err != nil {
@@ -31,11 +32,16 @@ func (r *DocRepoPG) Create(ctx context.Context, d *doc.Documentation) error {
 	return nil
 }
 
+// GetByID returns documentation with its articles. It returns doc.ErrNotFound if there is no such documentation.
 func (r *DocRepoPG) GetByID(ctx context.Context, docID int) (*doc.Documentation, error) {
-	q := "select d.id, d.name, d.default_highlight_language from documentation as d where d.id = $1"
+	q := `select d.id, d.name, coalesce(d.slug, ''), d.default_highlight_language, d.version from documentation as d
+			where d.id = $1 and d.deleted_at is null`
 
 	var d doc.Documentation
-	err := r.db.QueryRow(ctx, q, docID).Scan(&d.ID, &d.Name, &d.DefaultHighlightLanguage)
+	err := r.db.QueryRow(ctx, q, docID).Scan(&d.ID, &d.Name, &d.Slug, &d.DefaultHighlightLanguage, &d.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, doc.ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +55,8 @@ func (r *DocRepoPG) GetByID(ctx context.Context, docID int) (*doc.Documentation,
 }
 
 func (r *DocRepoPG) GetAll(ctx context.Context) ([]*doc.Documentation, error) {
-	q := "select d.id, d.name, d.default_highlight_language from documentation d"
+	q := `select d.id, d.name, coalesce(d.slug, ''), d.default_highlight_language from documentation d
+			where d.deleted_at is null`
 
 	rows, err := r.db.Query(ctx, q)
 	if err != nil {
@@ -60,7 +67,7 @@ func (r *DocRepoPG) GetAll(ctx context.Context) ([]*doc.Documentation, error) {
 	res := make([]*doc.Documentation, 0)
 	for rows.Next() {
 		d := doc.Documentation{}
-		err = rows.Scan(&d.ID, &d.Name, &d.DefaultHighlightLanguage)
+		err = rows.Scan(&d.ID, &d.Name, &d.Slug, &d.DefaultHighlightLanguage)
 		if err != nil {
 			return nil, err
 		}
@@ -76,31 +83,111 @@ func (r *DocRepoPG) GetAll(ctx context.Context) ([]*doc.Documentation, error) {
 	return res, nil
 }
 
+// Update saves documentation if it is still of d.Version and increments its version.
+// It returns doc.ErrConflict when documentation was updated by someone else.
 func (r *DocRepoPG) Update(ctx context.Context, d *doc.Documentation) error {
-	q := "update documentation d set name = $1, default_highlight_language = $2 where d.id = $3"
+	q := `update documentation d set name = $1, default_highlight_language = $2, version = version + 1
+			where d.id = $3 and d.version = $4`
 
-	commandTag, err := r.db.Exec(ctx, q, d.Name, d.DefaultHighlightLanguage, d.ID)
+	commandTag, err := r.db.Exec(ctx, q, d.Name, d.DefaultHighlightLanguage, d.ID, d.Version)
 	if err != nil {
 		return err
 	}
 
 	if commandTag.RowsAffected() != 1 {
 		log.Printf("update doc rows affected equals %v\n", commandTag.RowsAffected())
-		return errors.New("update doc rows affected not equals 1")
+		return doc.ErrConflict
 	}
+	d.Version++
 
 	return nil
 }
 
+// Delete moves documentation to trash. It keeps all relations of documentation to restore it later.
 func (r *DocRepoPG) Delete(ctx context.Context, docID int) error {
+	q := "update documentation t set deleted_at = now() where t.id = $1 and t.deleted_at is null"
+	commandTag, err := r.db.Exec(ctx, q, docID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		log.Printf("delete documentation rows affected equals %v\n", commandTag.RowsAffected())
+		return errors.New("documentation already deleted")
+	}
+
+	return nil
+}
+
+// Restore returns documentation from trash. Articles and examples moved to trash by DeleteCascade of the
+// documentation are restored too, items deleted separately stay in trash.
+func (r *DocRepoPG) Restore(ctx context.Context, docID int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	q := `update example e set deleted_at = null, deleted_with_documentation_id = null
+			where e.deleted_with_documentation_id = $1 and e.deleted_at is not null`
+	_, err = tx.Exec(ctx, q, docID)
+	if err != nil {
+		return err
+	}
+
+	q = `update article a set deleted_at = null, deleted_with_documentation_id = null
+			where a.deleted_with_documentation_id = $1 and a.deleted_at is not null`
+	_, err = tx.Exec(ctx, q, docID)
+	if err != nil {
+		return err
+	}
+
+	q = "update documentation t set deleted_at = null where t.id = $1 and t.deleted_at is not null"
+	commandTag, err := tx.Exec(ctx, q, docID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return trash.ErrNotInTrash
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Purge deletes documentation with all its relations permanently.
+func (r *DocRepoPG) Purge(ctx context.Context, docID int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
 	q := "delete from documentation_articles where documentation_id=$1"
-	_, err := r.db.Exec(ctx, q, docID)
+	_, err = tx.Exec(ctx, q, docID)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from documentation_version where documentation_id=$1"
+	_, err = tx.Exec(ctx, q, docID)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from webhook where documentation_id=$1"
+	_, err = tx.Exec(ctx, q, docID)
+	if err != nil {
+		return err
+	}
+
+	err = deleteSlugRedirects(ctx, tx, slugTableDoc, docID)
 	if err != nil {
 		return err
 	}
 
 	q = "delete from documentation where id=$1"
-	commandTag, err := r.db.Exec(ctx, q, docID)
+	commandTag, err := tx.Exec(ctx, q, docID)
 	if err != nil {
 		return err
 	}
@@ -110,5 +197,87 @@ func (r *DocRepoPG) Delete(ctx context.Context, docID int) error {
 		return errors.New("delete doc rows affected not equals 1")
 	}
 
-	return nil
+	return tx.Commit(ctx)
+}
+
+// SetSlug makes slug of documentation from its name. Previous slug leads to documentation after change.
+func (r *DocRepoPG) SetSlug(ctx context.Context, docID int, name string) error {
+	return setSlug(ctx, r.db, slugTableDoc, docID, name)
+}
+
+// GetIDBySlug returns id and current slug of documentation that has slug s now or had it before.
+func (r *DocRepoPG) GetIDBySlug(ctx context.Context, s string) (int, string, error) {
+	id, cur, err := getIDBySlug(ctx, r.db, slugTableDoc, s)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", doc.ErrNotFound
+	}
+
+	return id, cur, err
+}
+
+// exclusiveArticleIDs selects not deleted articles of documentation $1 that belong to no other documentation.
+const exclusiveArticleIDs = `
+	select da.article_id from documentation_articles da
+	join article a on a.id = da.article_id
+	where da.documentation_id = $1 and a.deleted_at is null and not exists(
+		select 1 from documentation_articles o
+		join documentation d on d.id = o.documentation_id
+		where o.article_id = da.article_id and o.documentation_id != $1 and d.deleted_at is null)
+	`
+
+// exclusiveExampleIDs selects not deleted examples of exclusive articles of documentation $1
+// that belong to no other article.
+const exclusiveExampleIDs = `
+	select distinct ae.example_id from article_examples ae
+	join example e on e.id = ae.example_id
+	where ae.article_id in (` + exclusiveArticleIDs + `) and e.deleted_at is null and not exists(
+		select 1 from article_examples o
+		join article a on a.id = o.article_id
+		where o.example_id = ae.example_id and a.deleted_at is null
+		and o.article_id not in (` + exclusiveArticleIDs + `))
+	`
+
+// DeleteCascade moves documentation to trash in one transaction with its exclusive articles and examples
+// according to cascade mode.
+func (r *DocRepoPG) DeleteCascade(ctx context.Context, docID int, cascade string) error {
+	if !doc.ValidCascade(cascade) {
+		return doc.ErrUnknownCascade
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	if cascade == doc.CascadeExamples {
+		q := `update example e set deleted_at = now(), deleted_with_documentation_id = $1
+				where e.id in (` + exclusiveExampleIDs + ")"
+		_, err = tx.Exec(ctx, q, docID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cascade == doc.CascadeArticles || cascade == doc.CascadeExamples {
+		q := `update article a set deleted_at = now(), deleted_with_documentation_id = $1
+				where a.id in (` + exclusiveArticleIDs + ")"
+		_, err = tx.Exec(ctx, q, docID)
+		if err != nil {
+			return err
+		}
+	}
+
+	q := "update documentation d set deleted_at = now() where d.id = $1 and d.deleted_at is null"
+	commandTag, err := tx.Exec(ctx, q, docID)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		log.Printf("delete documentation rows affected equals %v\n", commandTag.RowsAffected())
+		return errors.New("documentation already deleted")
+	}
+
+	return tx.Commit(ctx)
 }
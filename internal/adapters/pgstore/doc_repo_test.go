@@ -4,9 +4,11 @@ import (
 	"context"
 	"documentation-mini-app/internal/domain/article"
 	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/example"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDocRepoPG_CreateDoc(t *testing.T) {
@@ -35,7 +37,7 @@ func TestDocRepoPG_GetDoc(t *testing.T) {
 	docID := 1
 
 	_, err := s.Doc().GetByID(ctx, docID)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, doc.ErrNotFound)
 
 	d := doc.Documentation{
 		Name:                     "example",
@@ -107,3 +109,87 @@ func TestDocRepoPG_DeleteDoc(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, getDoc)
 }
+
+func TestDocRepoPG_DeleteCascade(t *testing.T) {
+	ctx := context.TODO()
+
+	s, truncate := TestStore(ctx, t, dbURL)
+	defer truncate(ctx, "documentation", "article", "example")
+
+	docs := []*doc.Documentation{{Name: "Go"}, {Name: "Rust"}}
+	for _, d := range docs {
+		require.NoError(t, s.Doc().Create(ctx, d))
+	}
+
+	// own, separate and trashed belong only to Go, shared belongs to both documentations.
+	own, shared, separate, trashed := &article.Article{Name: "own"}, &article.Article{Name: "shared"},
+		&article.Article{Name: "separate"}, &article.Article{Name: "trashed"}
+	for _, a := range []*article.Article{own, shared, separate, trashed} {
+		require.NoError(t, s.Article().Create(ctx, a))
+		require.NoError(t, s.Article().AddToDoc(ctx, a.ID, docs[0].ID))
+	}
+	require.NoError(t, s.Article().AddToDoc(ctx, shared.ID, docs[1].ID))
+	require.NoError(t, s.Article().Delete(ctx, trashed.ID))
+
+	// ownExa is only in exclusive article, sharedExa is also in shared article.
+	ownExa, sharedExa := &example.Example{Name: "own"}, &example.Example{Name: "shared"}
+	for _, e := range []*example.Example{ownExa, sharedExa} {
+		require.NoError(t, s.Example().Create(ctx, e))
+		require.NoError(t, s.Example().AddToArticle(ctx, e.ID, own.ID))
+	}
+	require.NoError(t, s.Example().AddToArticle(ctx, sharedExa.ID, shared.ID))
+
+	arts, err := s.Article().GetExclusiveByDocID(ctx, docs[0].ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{own.ID, separate.ID}, articleIDs(arts))
+
+	exas, err := s.Example().GetExclusiveByDocID(ctx, docs[0].ID)
+	require.NoError(t, err)
+	require.Len(t, exas, 1)
+	assert.Equal(t, ownExa.ID, exas[0].ID)
+
+	assert.ErrorIs(t, s.Doc().DeleteCascade(ctx, docs[0].ID, "all"), doc.ErrUnknownCascade)
+
+	// Article deleted separately in the same transaction has the same deletion time.
+	err = s.InTx(ctx, func(tx *Store) error {
+		err := tx.Article().Delete(ctx, separate.ID)
+		if err != nil {
+			return err
+		}
+
+		return tx.Doc().DeleteCascade(ctx, docs[0].ID, doc.CascadeExamples)
+	})
+	require.NoError(t, err)
+
+	deleted := trashNames(ctx, t, s)
+	assert.ElementsMatch(t, []string{"documentation " + docs[0].Name, "article own", "article separate",
+		"article trashed", "example own"}, deleted)
+
+	require.NoError(t, s.Doc().Restore(ctx, docs[0].ID))
+	assert.ElementsMatch(t, []string{"article separate", "article trashed"}, trashNames(ctx, t, s))
+
+	_, err = s.Example().GetByID(ctx, ownExa.ID)
+	assert.NoError(t, err)
+}
+
+func articleIDs(arts []article.Article) []int {
+	res := make([]int, 0, len(arts))
+	for _, a := range arts {
+		res = append(res, a.ID)
+	}
+
+	return res
+}
+
+// trashNames returns entity types and names of items in trash.
+func trashNames(ctx context.Context, t *testing.T, s *Store) []string {
+	items, err := s.Trash().GetAll(ctx)
+	require.NoError(t, err)
+
+	res := make([]string, 0, len(items))
+	for _, i := range items {
+		res = append(res, i.EntityType+" "+i.Name)
+	}
+
+	return res
+}
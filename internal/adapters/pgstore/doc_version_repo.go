@@ -0,0 +1,115 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/doc"
+	"encoding/json"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is postgres error code of unique constraint violation.
+const uniqueViolation = "23505"
+
+type DocVersionRepoPG struct {
+	db dbtx
+}
+
+func NewDocVersionRepoPG(db dbtx) *DocVersionRepoPG {
+	return &DocVersionRepoPG{db: db}
+}
+
+// Create saves version with its snapshot. It returns doc.ErrVersionExists if name is already used.
+func (r *DocVersionRepoPG) Create(ctx context.Context, v *doc.Version) error {
+	snapshot, err := json.Marshal(v.Doc)
+	if err != nil {
+		return err
+	}
+
+	q := `insert into documentation_version(documentation_id, name, snapshot) values($1, $2, $3)
+			returning id, created_at`
+
+	err = r.db.QueryRow(ctx, q, v.DocID, v.Name, snapshot).Scan(&v.ID, &v.CreatedAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return doc.ErrVersionExists
+	}
+
+	return err
+}
+
+// GetByName returns version of documentation with name. It returns doc.ErrVersionNotFound if there is no such version
+// or documentation is in trash.
+func (r *DocVersionRepoPG) GetByName(ctx context.Context, docID int, name string) (*doc.Version, error) {
+	q := `select v.id, v.documentation_id, v.name, v.created_at, v.snapshot from documentation_version v
+			join documentation d on d.id = v.documentation_id
+			where v.documentation_id = $1 and v.name = $2 and d.deleted_at is null`
+
+	v, err := r.get(ctx, q, docID, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, doc.ErrVersionNotFound
+	}
+
+	return v, err
+}
+
+// GetLatest returns the last published version of documentation. It returns nil if documentation has no versions
+// or is in trash.
+func (r *DocVersionRepoPG) GetLatest(ctx context.Context, docID int) (*doc.Version, error) {
+	q := `select v.id, v.documentation_id, v.name, v.created_at, v.snapshot from documentation_version v
+			join documentation d on d.id = v.documentation_id
+			where v.documentation_id = $1 and d.deleted_at is null order by v.created_at desc, v.id desc limit 1`
+
+	v, err := r.get(ctx, q, docID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+
+	return v, err
+}
+
+func (r *DocVersionRepoPG) get(ctx context.Context, q string, args ...any) (*doc.Version, error) {
+	var (
+		v        doc.Version
+		snapshot []byte
+	)
+
+	err := r.db.QueryRow(ctx, q, args...).Scan(&v.ID, &v.DocID, &v.Name, &v.CreatedAt, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(snapshot, &v.Doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// GetByDocID returns versions of documentation without snapshots, the latest first.
+func (r *DocVersionRepoPG) GetByDocID(ctx context.Context, docID int) ([]doc.Version, error) {
+	q := `select v.id, v.documentation_id, v.name, v.created_at from documentation_version v
+			join documentation d on d.id = v.documentation_id
+			where v.documentation_id = $1 and d.deleted_at is null order by v.created_at desc, v.id desc`
+
+	rows, err := r.db.Query(ctx, q, docID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]doc.Version, 0)
+	for rows.Next() {
+		v := doc.Version{}
+		err = rows.Scan(&v.ID, &v.DocID, &v.Name, &v.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+
+	return res, nil
+}
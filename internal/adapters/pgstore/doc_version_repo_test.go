@@ -0,0 +1,45 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/doc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocVersionRepoPG_TrashedDoc(t *testing.T) {
+	ctx := context.TODO()
+
+	s, truncate := TestStore(ctx, t, dbURL)
+	defer truncate(ctx, "documentation", "documentation_version")
+
+	d := &doc.Documentation{Name: "Go"}
+	require.NoError(t, s.Doc().Create(ctx, d))
+
+	v := &doc.Version{DocID: d.ID, Name: "v1", Doc: d}
+	require.NoError(t, s.DocVersion().Create(ctx, v))
+
+	got, err := s.DocVersion().GetByName(ctx, d.ID, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, d.Name, got.Doc.Name)
+
+	require.NoError(t, s.Doc().Delete(ctx, d.ID))
+
+	_, err = s.DocVersion().GetByName(ctx, d.ID, "v1")
+	assert.ErrorIs(t, err, doc.ErrVersionNotFound)
+
+	latest, err := s.DocVersion().GetLatest(ctx, d.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, latest)
+
+	list, err := s.DocVersion().GetByDocID(ctx, d.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+
+	require.NoError(t, s.Doc().Restore(ctx, d.ID))
+
+	_, err = s.DocVersion().GetByName(ctx, d.ID, "v1")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,125 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/event"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// eventChannel is notification channel of content changes.
+const eventChannel = "content_changes"
+
+// changeDocIDs selects documentations that contain entity $1 by its type. Articles and examples belong
+// to every documentation that contains them.
+var changeDocIDs = map[string]string{
+	"documentation": `select $1::integer`,
+	"version":       `select v.documentation_id from documentation_version v where v.id = $1`,
+	"article":       `select da.documentation_id from documentation_articles da where da.article_id = $1`,
+	"example": `select distinct da.documentation_id from documentation_articles da
+		join article_examples ae on ae.article_id = da.article_id where ae.example_id = $1`,
+}
+
+// changeArticleIDs selects articles that contain entity $1 by its type.
+var changeArticleIDs = map[string]string{
+	"article": `select $1::integer`,
+	"example": `select ae.article_id from article_examples ae where ae.example_id = $1`,
+}
+
+// changeDraft selects whether entity $1 is unpublished by its type. Other entities are always published.
+var changeDraft = map[string]string{
+	"article": `select not a.published from article a where a.id = $1`,
+	"example": `select not e.published from example e where e.id = $1`,
+}
+
+type EventRepoPG struct {
+	db dbtx
+	s  *Store
+}
+
+func NewEventRepoPG(db dbtx, s *Store) *EventRepoPG {
+	return &EventRepoPG{db: db, s: s}
+}
+
+// Emit sends change of entity to webhooks and listeners of all server instances. Change of unpublished
+// entity is sent only to listeners.
+func (r *EventRepoPG) Emit(ctx context.Context, t string, entityID int) error {
+	c := event.New(t, entityID)
+
+	q, ok := changeDocIDs[c.EntityType]
+	if !ok || !event.Known(t) {
+		return fmt.Errorf("unknown event %q", t)
+	}
+
+	var err error
+	c.DocIDs, err = queryInts(ctx, r.db, q, entityID)
+	if err != nil {
+		return err
+	}
+
+	if q, ok := changeArticleIDs[c.EntityType]; ok {
+		c.ArticleIDs, err = queryInts(ctx, r.db, q, entityID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if q, ok := changeDraft[c.EntityType]; ok {
+		err = r.db.QueryRow(ctx, q, entityID).Scan(&c.Draft)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !c.Draft {
+		err = r.s.Webhook().Enqueue(ctx, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, "select pg_notify($1, $2)", eventChannel, string(payload))
+	return err
+}
+
+// Listen calls handle for every change emitted by any server instance until ctx is done or connection fails.
+func (r *EventRepoPG) Listen(ctx context.Context, handle func(event.Change)) error {
+	conn, err := r.s.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, "listen "+eventChannel)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, err := conn.Exec(context.Background(), "unlisten "+eventChannel)
+		if err != nil && !conn.Conn().IsClosed() {
+			log.Printf("unlisten: %v\n", err)
+		}
+	}()
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var c event.Change
+		err = json.Unmarshal([]byte(n.Payload), &c)
+		if err != nil {
+			log.Printf("decode change %q: %v\n", n.Payload, err)
+			continue
+		}
+
+		handle(c)
+	}
+}
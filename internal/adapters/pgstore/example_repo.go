@@ -3,22 +3,24 @@ package pgstore
 import (
 	"context"
 	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/trash"
 	"errors"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 	"log"
 )
 
 type ExampleRepoPG struct {
-	db *pgxpool.Pool
+	db dbtx
 }
 
-func NewExampleRepoPG(db *pgxpool.Pool) *ExampleRepoPG {
+func NewExampleRepoPG(db dbtx) *ExampleRepoPG {
 	return &ExampleRepoPG{db: db}
 }
 
 func (r *ExampleRepoPG) GetByArticleID(ctx context.Context, artID int) ([]example.Example, error) {
-	q := `SELECT e.id, e.name, e.description, e.code, e.output, ae.priority FROM article_examples ae
-			JOIN example e on e.id = ae.example_id WHERE ae.article_id = $1`
+	q := `SELECT e.id, e.name, e.description, e.code, e.output, coalesce(e.highlight_language, ''),
+			e.auto_format, e.published, e.version, ae.priority FROM article_examples ae
+			JOIN example e on e.id = ae.example_id WHERE ae.article_id = $1 and e.deleted_at is null`
 
 	rows, err := r.db.Query(ctx, q, artID)
 	if err != nil {
@@ -29,21 +31,45 @@ func (r *ExampleRepoPG) GetByArticleID(ctx context.Context, artID int) ([]exampl
 	res := make([]example.Example, 0)
 	for rows.Next() {
 		ex := example.Example{}
-		err = rows.Scan(&ex.ID, &ex.Name, &ex.Description, &ex.Code, &ex.Output, &ex.Priority)
+		err = rows.Scan(&ex.ID, &ex.Name, &ex.Description, &ex.Code, &ex.Output, &ex.HighlightLanguage,
+			&ex.AutoFormat, &ex.Published, &ex.Version, &ex.Priority)
 		if err != nil {
 			return nil, err
 		}
 		res = append(res, ex)
 	}
+	rows.Close()
+
+	for i := range res {
+		res[i].Files, err = r.GetFiles(ctx, res[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return res, nil
 }
 
 func (r *ExampleRepoPG) GetByID(ctx context.Context, id int) (*example.Example, error) {
-	q := `select e.id, e.name, e.description, e.code, e.output FROM example e where e.id = $1`
+	q := `select e.id, e.name, e.description, e.code, e.output, coalesce(e.highlight_language, ''), e.auto_format,
+			e.published, e.version FROM example e where e.id = $1 and e.deleted_at is null`
 
 	var exa example.Example
-	err := r.db.QueryRow(ctx, q, id).Scan(&exa.ID, &exa.Name, &exa.Description, &exa.Code, &exa.Output)
+	err := r.db.QueryRow(ctx, q, id).Scan(&exa.ID, &exa.Name, &exa.Description, &exa.Code, &exa.Output,
+		&exa.HighlightLanguage, &exa.AutoFormat, &exa.Published, &exa.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, example.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	exa.Files, err = r.GetFiles(ctx, exa.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	exa.Tags, err = r.GetTags(ctx, exa.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -51,47 +77,288 @@ func (r *ExampleRepoPG) GetByID(ctx context.Context, id int) (*example.Example,
 	return &exa, nil
 }
 
+// GetAll returns all examples without their files.
+func (r *ExampleRepoPG) GetAll(ctx context.Context) ([]example.Example, error) {
+	q := `select e.id, e.name, e.description, e.code, e.output, coalesce(e.highlight_language, ''), e.auto_format,
+			e.published, e.version FROM example e where e.deleted_at is null order by e.id`
+
+	return r.query(ctx, q)
+}
+
+// GetWithoutArticle returns examples that don't belong to any article.
+func (r *ExampleRepoPG) GetWithoutArticle(ctx context.Context) ([]example.Example, error) {
+	q := `select e.id, e.name, e.description, e.code, e.output, coalesce(e.highlight_language, ''), e.auto_format,
+			e.published, e.version FROM example e
+			where e.deleted_at is null and not exists(
+				select 1 from article_examples ae
+				join article a on a.id = ae.article_id
+				where ae.example_id = e.id and a.deleted_at is null)
+			order by e.id`
+
+	return r.query(ctx, q)
+}
+
+// GetExclusiveByDocID returns examples of exclusive articles of documentation that belong to no other article.
+func (r *ExampleRepoPG) GetExclusiveByDocID(ctx context.Context, docID int) ([]example.Example, error) {
+	q := `select e.id, e.name, e.description, e.code, e.output, coalesce(e.highlight_language, ''), e.auto_format,
+			e.published, e.version FROM example e
+			where e.id in (` + exclusiveExampleIDs + `) order by e.name, e.id`
+
+	return r.query(ctx, q, docID)
+}
+
+func (r *ExampleRepoPG) query(ctx context.Context, q string, args ...any) ([]example.Example, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]example.Example, 0)
+	for rows.Next() {
+		exa := example.Example{}
+		err = rows.Scan(&exa.ID, &exa.Name, &exa.Description, &exa.Code, &exa.Output,
+			&exa.HighlightLanguage, &exa.AutoFormat, &exa.Published, &exa.Version)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, exa)
+	}
+
+	return res, nil
+}
+
+// GetFiles returns files of multi-file example ordered by position.
+func (r *ExampleRepoPG) GetFiles(ctx context.Context, exaID int) ([]example.File, error) {
+	q := `select f.id, f.name, coalesce(f.highlight_language, ''), f.code from example_file f
+			where f.example_id = $1 order by f.position, f.id`
+
+	rows, err := r.db.Query(ctx, q, exaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]example.File, 0)
+	for rows.Next() {
+		f := example.File{}
+		err = rows.Scan(&f.ID, &f.Name, &f.HighlightLanguage, &f.Code)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, f)
+	}
+
+	return res, nil
+}
+
 func (r *ExampleRepoPG) Create(ctx context.Context, exa *example.Example) error {
-	q := "insert into example(name, description, code, output) values($1, $2, $3, $4) returning id"
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	q := `insert into example(name, description, code, output, highlight_language, auto_format, published, code_hash)
+			values($1, $2, $3, $4, $5, $6, $7, $8) returning id, version`
 
 	var exaID int
-	err := r.db.QueryRow(ctx, q, exa.Name, exa.Description, exa.Code, exa.Output).Scan(&exaID)
+	err = tx.QueryRow(ctx, q, exa.Name, exa.Description, exa.Code, exa.Output,
+		exa.HighlightLanguage, exa.AutoFormat, exa.Published, example.CodeHash(*exa)).Scan(&exaID, &exa.Version)
+	if err != nil {
+		return err
+	}
+
+	err = insertFiles(ctx, tx, exaID, exa.Files)
+	if err != nil {
+		return err
+	}
+
+	err = insertTags(ctx, tx, exaID, exa.Tags)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
 	exa.ID = exaID
 
+	return nil
+}
+
+// GetByCodeHash returns examples which code has hash made by example.CodeHash.
+func (r *ExampleRepoPG) GetByCodeHash(ctx context.Context, hash string) ([]example.Example, error) {
+	q := `select e.id, e.name, e.description, e.code, e.output, coalesce(e.highlight_language, ''), e.auto_format,
+			e.published, e.version FROM example e where e.code_hash = $1 and e.code_hash != '' and e.deleted_at is null
+			order by e.id`
+
+	return r.query(ctx, q, hash)
+}
+
+// GetIDsWithoutCodeHash returns ids of examples created before code hashes were introduced.
+// Examples in trash are skipped until they are restored.
+func (r *ExampleRepoPG) GetIDsWithoutCodeHash(ctx context.Context) ([]int, error) {
+	return queryInts(ctx, r.db, "select e.id from example e where e.code_hash is null and e.deleted_at is null")
+}
+
+func (r *ExampleRepoPG) SetCodeHash(ctx context.Context, exaID int, hash string) error {
+	_, err := r.db.Exec(ctx, "update example e set code_hash = $1 where e.id = $2", hash, exaID)
 	return err
 }
 
+// InArticle reports whether example is one of examples of article.
+func (r *ExampleRepoPG) InArticle(ctx context.Context, exaID int, artID int) (bool, error) {
+	q := `select exists(select 1 from article_examples ae join example e on e.id = ae.example_id
+			where ae.article_id = $1 and ae.example_id = $2 and e.deleted_at is null)`
+
+	var ok bool
+	err := r.db.QueryRow(ctx, q, artID, exaID).Scan(&ok)
+
+	return ok, err
+}
+
+// AddToArticle links example to article. It does nothing if they are already linked.
 func (r *ExampleRepoPG) AddToArticle(ctx context.Context, exaID int, artID int) error {
-	q := "insert into article_examples(article_id, example_id) values($1, $2)"
+	q := "insert into article_examples(article_id, example_id) values($1, $2) on conflict do nothing"
 	_, err := r.db.Exec(ctx, q, artID, exaID)
 	return err
 }
 
+// LockVersion returns current version of example and locks it until the end of transaction,
+// so the example can't be published while the version is in use.
+func (r *ExampleRepoPG) LockVersion(ctx context.Context, id int) (int, error) {
+	q := "select e.version from example e where e.id = $1 and e.deleted_at is null for update"
+
+	var version int
+	err := r.db.QueryRow(ctx, q, id).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, example.ErrNotFound
+	}
+
+	return version, err
+}
+
+// Update saves example with its files and tags if it is still of exa.Version and increments its version.
+// It returns example.ErrConflict when example was updated by someone else.
 func (r *ExampleRepoPG) Update(ctx context.Context, exa *example.Example) error {
-	q := "update example e set name = $1, description = $2, code = $3, output = $4 where e.id = $5"
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	q := `update example e set name = $1, description = $2, code = $3, output = $4,
+			highlight_language = $5, auto_format = $6, code_hash = $7, version = version + 1
+			where e.id = $8 and e.version = $9`
 
-	commandTag, err := r.db.Exec(ctx, q, exa.Name, exa.Description, exa.Code, exa.Output, exa.ID)
+	commandTag, err := tx.Exec(ctx, q, exa.Name, exa.Description, exa.Code, exa.Output,
+		exa.HighlightLanguage, exa.AutoFormat, example.CodeHash(*exa), exa.ID, exa.Version)
 	if err != nil {
 		return err
 	}
 
 	if commandTag.RowsAffected() != 1 {
 		log.Printf("update example rows affected equals %v\n", commandTag.RowsAffected())
-		return errors.New("update example rows affected not equals 1")
+		return example.ErrConflict
+	}
+
+	_, err = tx.Exec(ctx, "delete from example_file where example_id=$1", exa.ID)
+	if err != nil {
+		return err
+	}
+
+	err = insertFiles(ctx, tx, exa.ID, exa.Files)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "delete from example_tags where example_id=$1", exa.ID)
+	if err != nil {
+		return err
+	}
+
+	err = insertTags(ctx, tx, exa.ID, exa.Tags)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
 	}
+	exa.Version++
 
 	return nil
 }
 
+// Delete moves example to trash. It keeps all relations of example to restore it later.
 func (r *ExampleRepoPG) Delete(ctx context.Context, id int) error {
+	q := "update example t set deleted_at = now() where t.id = $1 and t.deleted_at is null"
+	commandTag, err := r.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		log.Printf("delete example rows affected equals %v\n", commandTag.RowsAffected())
+		return errors.New("example already deleted")
+	}
+
+	return nil
+}
+
+// Restore returns example from trash.
+func (r *ExampleRepoPG) Restore(ctx context.Context, id int) error {
+	q := `update example t set deleted_at = null, deleted_with_documentation_id = null
+			where t.id = $1 and t.deleted_at is not null`
+	commandTag, err := r.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return trash.ErrNotInTrash
+	}
+
+	return nil
+}
+
+// Purge deletes example with all its relations permanently.
+func (r *ExampleRepoPG) Purge(ctx context.Context, id int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
 	q := "delete from article_examples where example_id=$1"
-	_, err := r.db.Exec(ctx, q, id)
+	_, err = tx.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from example_file where example_id=$1"
+	_, err = tx.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from example_tags where example_id=$1"
+	_, err = tx.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from example_translation where example_id=$1"
+	_, err = tx.Exec(ctx, q, id)
 	if err != nil {
 		return err
 	}
 
 	q = "delete from example where id=$1"
-	commandTag, err := r.db.Exec(ctx, q, id)
+	commandTag, err := tx.Exec(ctx, q, id)
 	if err != nil {
 		return err
 	}
@@ -101,5 +368,57 @@ func (r *ExampleRepoPG) Delete(ctx context.Context, id int) error {
 		return errors.New("example already deleted")
 	}
 
+	return tx.Commit(ctx)
+}
+
+// GetTags returns tags of example ordered by name.
+func (r *ExampleRepoPG) GetTags(ctx context.Context, exaID int) ([]string, error) {
+	return queryStrings(ctx, r.db, "select t.tag from example_tags t where t.example_id = $1 order by t.tag", exaID)
+}
+
+// Search returns examples whose name or description contains query and that are marked by tag.
+// Empty query or tag matches any example.
+func (r *ExampleRepoPG) Search(ctx context.Context, query string, tag string) ([]example.Example, error) {
+	q := `select e.id, e.name, e.description, e.code, e.output, coalesce(e.highlight_language, ''), e.auto_format,
+			e.published, e.version FROM example e
+			where e.deleted_at is null
+			and (e.name ilike '%' || $1 || '%' escape '\' or e.description ilike '%' || $1 || '%' escape '\')
+			and ($2 = '' or exists(select 1 from example_tags t where t.example_id = e.id and t.tag = $2))
+			order by e.name, e.id`
+
+	return r.query(ctx, q, escapeLike(query), tag)
+}
+
+func (r *ExampleRepoPG) SetPublished(ctx context.Context, exaID int, published bool) error {
+	q := "update example e set published = $1 where e.id = $2"
+	_, err := r.db.Exec(ctx, q, published, exaID)
+	return err
+}
+
+func insertFiles(ctx context.Context, tx pgx.Tx, exaID int, files []example.File) error {
+	q := `insert into example_file(example_id, name, highlight_language, code, position)
+			values($1, $2, $3, $4, $5) returning id`
+
+	for i := range files {
+		err := tx.QueryRow(ctx, q, exaID, files[i].Name, files[i].HighlightLanguage, files[i].Code, i).
+			Scan(&files[i].ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func insertTags(ctx context.Context, tx pgx.Tx, exaID int, tags []string) error {
+	q := "insert into example_tags(example_id, tag) values($1, $2) on conflict do nothing"
+
+	for _, t := range tags {
+		_, err := tx.Exec(ctx, q, exaID, t)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
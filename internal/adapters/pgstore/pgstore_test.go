@@ -44,7 +44,9 @@ func TestMain(m *testing.M) {
 	}
 
 	err := CheckTablesExistence("documentation", "article", "example",
-		"documentation_articles", "article_examples")
+		"documentation_articles", "article_examples", "example_file", "article_link", "documentation_version",
+		"revision", "comment_thread", "comment", "article_tags", "example_tags",
+		"slug_redirect", "audit_log", "webhook", "webhook_delivery", "article_translation", "example_translation")
 	if err != nil {
 		log.Panicln(err)
 	}
@@ -0,0 +1,164 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/review"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5"
+	"log"
+)
+
+type RevisionRepoPG struct {
+	db dbtx
+}
+
+func NewRevisionRepoPG(db dbtx) *RevisionRepoPG {
+	return &RevisionRepoPG{db: db}
+}
+
+func marshalContent(rev *review.Revision) ([]byte, error) {
+	switch rev.EntityType {
+	case review.EntityArticle:
+		return json.Marshal(rev.Article)
+	case review.EntityExample:
+		return json.Marshal(rev.Example)
+	}
+
+	return nil, fmt.Errorf("unknown revision entity type %q", rev.EntityType)
+}
+
+func unmarshalContent(rev *review.Revision, content []byte) error {
+	switch rev.EntityType {
+	case review.EntityArticle:
+		return json.Unmarshal(content, &rev.Article)
+	case review.EntityExample:
+		return json.Unmarshal(content, &rev.Example)
+	}
+
+	return fmt.Errorf("unknown revision entity type %q", rev.EntityType)
+}
+
+func (r *RevisionRepoPG) Create(ctx context.Context, rev *review.Revision) error {
+	content, err := marshalContent(rev)
+	if err != nil {
+		return err
+	}
+
+	q := `insert into revision(entity_type, entity_id, content, status, author, reviewer)
+			values($1, $2, $3, $4, $5, $6) returning id, created_at, updated_at`
+
+	return r.db.QueryRow(ctx, q, rev.EntityType, rev.EntityID, content, rev.Status, rev.Author, rev.Reviewer).
+		Scan(&rev.ID, &rev.CreatedAt, &rev.UpdatedAt)
+}
+
+func (r *RevisionRepoPG) Update(ctx context.Context, rev *review.Revision) error {
+	content, err := marshalContent(rev)
+	if err != nil {
+		return err
+	}
+
+	q := `update revision r set content = $1, status = $2, reviewer = $3, updated_at = now()
+			where r.id = $4 returning updated_at`
+
+	err = r.db.QueryRow(ctx, q, content, rev.Status, rev.Reviewer, rev.ID).Scan(&rev.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		log.Printf("update revision %v: not found\n", rev.ID)
+		return errors.New("update revision rows affected not equals 1")
+	}
+
+	return err
+}
+
+const revisionColumns = `r.id, r.entity_type, r.entity_id, r.content, r.status, r.author, r.reviewer,
+	r.created_at, r.updated_at`
+
+func (r *RevisionRepoPG) GetByID(ctx context.Context, id int) (*review.Revision, error) {
+	q := `select ` + revisionColumns + ` from revision r where r.id = $1`
+
+	revs, err := r.query(ctx, q, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(revs) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return &revs[0], nil
+}
+
+// GetOpen returns not published revision of entity by author. It returns nil if there is no such revision.
+func (r *RevisionRepoPG) GetOpen(ctx context.Context, entityType string, entityID int, author string,
+) (*review.Revision, error) {
+	q := `select ` + revisionColumns + ` from revision r
+			where r.entity_type = $1 and r.entity_id = $2 and r.author = $3 and r.status != $4
+			order by r.id desc limit 1`
+
+	revs, err := r.query(ctx, q, entityType, entityID, author, review.StatusPublished)
+	if err != nil || len(revs) == 0 {
+		return nil, err
+	}
+
+	return &revs[0], nil
+}
+
+// GetOpenByEntity returns all not published revisions of entity.
+func (r *RevisionRepoPG) GetOpenByEntity(ctx context.Context, entityType string, entityID int,
+) ([]review.Revision, error) {
+	q := `select ` + revisionColumns + ` from revision r
+			where r.entity_type = $1 and r.entity_id = $2 and r.status != $3 order by r.id`
+
+	return r.query(ctx, q, entityType, entityID, review.StatusPublished)
+}
+
+// GetByStatus returns revisions with status, the oldest first.
+func (r *RevisionRepoPG) GetByStatus(ctx context.Context, statuses ...review.Status) ([]review.Revision, error) {
+	q := `select ` + revisionColumns + ` from revision r where r.status = any($1) order by r.updated_at, r.id`
+
+	strs := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		strs = append(strs, string(s))
+	}
+
+	return r.query(ctx, q, strs)
+}
+
+func (r *RevisionRepoPG) query(ctx context.Context, q string, args ...any) ([]review.Revision, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]review.Revision, 0)
+	for rows.Next() {
+		var (
+			rev     review.Revision
+			content []byte
+		)
+
+		err = rows.Scan(&rev.ID, &rev.EntityType, &rev.EntityID, &content, &rev.Status, &rev.Author, &rev.Reviewer,
+			&rev.CreatedAt, &rev.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		err = unmarshalContent(&rev, content)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, rev)
+	}
+
+	return res, nil
+}
+
+// DeleteByEntity deletes all revisions of entity.
+func (r *RevisionRepoPG) DeleteByEntity(ctx context.Context, entityType string, entityID int) error {
+	q := "delete from revision where entity_type = $1 and entity_id = $2"
+	_, err := r.db.Exec(ctx, q, entityType, entityID)
+	return err
+}
@@ -0,0 +1,116 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/slug"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Tables of entities with slugs. Table name is also used as entity name in slug_redirect.
+const (
+	slugTableDoc     = "documentation"
+	slugTableArticle = "article"
+)
+
+// slugAttempts limits retries of setSlug when concurrent rename takes the same slug.
+const slugAttempts = 5
+
+// setSlug sets unique slug made from name to entity with id. Previous slug of entity is kept as redirect.
+// Slug is not changed if it is already made from name.
+func setSlug(ctx context.Context, db dbtx, table string, id int, name string) error {
+	var err error
+	for i := 0; i < slugAttempts; i++ {
+		err = trySetSlug(ctx, db, table, id, name)
+
+		// Free slug was taken by concurrent rename between check and update, the next free one is looked for.
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation {
+			return err
+		}
+	}
+
+	return err
+}
+
+func trySetSlug(ctx context.Context, db dbtx, table string, id int, name string) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	var cur string
+	q := fmt.Sprintf("select coalesce(t.slug, '') from %s t where t.id = $1 for update", table)
+	err = tx.QueryRow(ctx, q, id).Scan(&cur)
+	if err != nil {
+		return err
+	}
+
+	base := slug.Make(name)
+	if slug.HasBase(cur, base) {
+		return nil
+	}
+
+	var s string
+	q = fmt.Sprintf("select exists(select 1 from %s t where t.slug = $1)", table)
+	for n := 1; ; n++ {
+		s = slug.WithSuffix(base, n)
+
+		var taken bool
+		err = tx.QueryRow(ctx, q, s).Scan(&taken)
+		if err != nil {
+			return err
+		}
+		if !taken {
+			break
+		}
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf("update %s t set slug = $1 where t.id = $2", table), s, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "delete from slug_redirect where entity = $1 and slug = $2", table, s)
+	if err != nil {
+		return err
+	}
+
+	if cur != "" {
+		q = `insert into slug_redirect(entity, slug, entity_id) values($1, $2, $3)
+			on conflict (entity, slug) do update set entity_id = excluded.entity_id`
+		_, err = tx.Exec(ctx, q, table, cur, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// getIDBySlug returns id and current slug of not deleted entity that has slug s now or had it before.
+func getIDBySlug(ctx context.Context, db dbtx, table string, s string) (int, string, error) {
+	q := fmt.Sprintf(`
+	select id, slug from (
+		select t.id, t.slug, 0 as prio from %[1]s t where t.slug = $1 and t.deleted_at is null
+		union all
+		select t.id, t.slug, 1 as prio from slug_redirect r
+		join %[1]s t on t.id = r.entity_id
+		where r.entity = $2 and r.slug = $1 and t.deleted_at is null
+	) found order by prio limit 1
+	`, table)
+
+	var id int
+	var cur string
+	err := db.QueryRow(ctx, q, s, table).Scan(&id, &cur)
+
+	return id, cur, err
+}
+
+// deleteSlugRedirects deletes previous slugs of entity.
+func deleteSlugRedirects(ctx context.Context, db dbtx, table string, id int) error {
+	_, err := db.Exec(ctx, "delete from slug_redirect where entity = $1 and entity_id = $2", table, id)
+	return err
+}
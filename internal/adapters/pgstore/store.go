@@ -2,16 +2,42 @@ package pgstore
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"log"
+	"strings"
 )
 
+// dbtx is connection pool or transaction. Repositories run queries with it, so the same repository code
+// works inside and outside of transaction. Begin inside transaction creates savepoint.
+type dbtx interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
 type Store struct {
-	db          *pgxpool.Pool
+	pool *pgxpool.Pool
+	// db is pool or transaction of store returned by InTx.
+	db          dbtx
 	docRepo     *DocRepoPG
 	articleRepo *ArticleRepoPG
 	exampleRepo *ExampleRepoPG
+
+	docVersionRepo *DocVersionRepoPG
+	revisionRepo   *RevisionRepoPG
+	commentRepo    *CommentRepoPG
+	tagRepo        *TagRepoPG
+	trashRepo      *TrashRepoPG
+	auditRepo      *AuditRepoPG
+	webhookRepo    *WebhookRepoPG
+	eventRepo      *EventRepoPG
+
+	translationRepo *TranslationRepoPG
 }
 
 // New connects database. Need call Close after this.
@@ -28,7 +54,7 @@ func New(ctx context.Context, dbURL string) (*Store, error) {
 
 // open connects database. Need call Close after this.
 func (s *Store) open(ctx context.Context, dbURL string) error {
-	if s.db != nil {
+	if s.pool != nil {
 		log.Println("trying to open store that not closed")
 		s.Close()
 	}
@@ -44,18 +70,40 @@ func (s *Store) open(ctx context.Context, dbURL string) error {
 		return fmt.Errorf("db ping: %w", err)
 	}
 
-	s.db = pool
+	s.pool, s.db = pool, pool
 
 	return nil
 }
 
 func (s *Store) Close() {
-	if s.db == nil {
+	if s.pool == nil {
 		log.Println("trying to close nil db")
 		return
 	}
 
-	s.db.Close()
+	s.pool.Close()
+}
+
+// InTx calls fn with store which repositories run queries in one transaction. Transaction is committed
+// if fn succeeds and rolled back otherwise. InTx of store inside fn reuses its transaction, so usecases
+// can be composed.
+func (s *Store) InTx(ctx context.Context, fn func(tx *Store) error) error {
+	if _, ok := s.db.(pgx.Tx); ok {
+		return fn(s)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	err = fn(&Store{pool: s.pool, db: tx})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (s *Store) Doc() *DocRepoPG {
@@ -81,3 +129,133 @@ func (s *Store) Example() *ExampleRepoPG {
 
 	return s.exampleRepo
 }
+
+func (s *Store) DocVersion() *DocVersionRepoPG {
+	if s.docVersionRepo == nil {
+		s.docVersionRepo = NewDocVersionRepoPG(s.db)
+	}
+
+	return s.docVersionRepo
+}
+
+func (s *Store) Revision() *RevisionRepoPG {
+	if s.revisionRepo == nil {
+		s.revisionRepo = NewRevisionRepoPG(s.db)
+	}
+
+	return s.revisionRepo
+}
+
+func (s *Store) Comment() *CommentRepoPG {
+	if s.commentRepo == nil {
+		s.commentRepo = NewCommentRepoPG(s.db)
+	}
+
+	return s.commentRepo
+}
+
+func (s *Store) Tag() *TagRepoPG {
+	if s.tagRepo == nil {
+		s.tagRepo = NewTagRepoPG(s.db)
+	}
+
+	return s.tagRepo
+}
+
+func (s *Store) Trash() *TrashRepoPG {
+	if s.trashRepo == nil {
+		s.trashRepo = NewTrashRepoPG(s.db)
+	}
+
+	return s.trashRepo
+}
+
+func (s *Store) Audit() *AuditRepoPG {
+	if s.auditRepo == nil {
+		s.auditRepo = NewAuditRepoPG(s.db)
+	}
+
+	return s.auditRepo
+}
+
+func (s *Store) Webhook() *WebhookRepoPG {
+	if s.webhookRepo == nil {
+		s.webhookRepo = NewWebhookRepoPG(s.db)
+	}
+
+	return s.webhookRepo
+}
+
+func (s *Store) Event() *EventRepoPG {
+	if s.eventRepo == nil {
+		s.eventRepo = NewEventRepoPG(s.db, s)
+	}
+
+	return s.eventRepo
+}
+
+func (s *Store) Translation() *TranslationRepoPG {
+	if s.translationRepo == nil {
+		s.translationRepo = NewTranslationRepoPG(s.db)
+	}
+
+	return s.translationRepo
+}
+
+// rollback rolls back tx. It is no-op if tx already committed.
+func rollback(ctx context.Context, tx pgx.Tx) {
+	err := tx.Rollback(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		log.Printf("tx rollback: %v\n", err)
+	}
+}
+
+// likeEscaper escapes wildcards of like patterns, so user input matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike returns s escaped for use in like pattern with escape '\' clause.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// queryStrings returns single text column selected by q.
+func queryStrings(ctx context.Context, db dbtx, q string, args ...any) ([]string, error) {
+	rows, err := db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]string, 0)
+	for rows.Next() {
+		var t string
+		err = rows.Scan(&t)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+
+	return res, nil
+}
+
+// queryInts returns single integer column selected by q.
+func queryInts(ctx context.Context, db dbtx, q string, args ...any) ([]int, error) {
+	rows, err := db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]int, 0)
+	for rows.Next() {
+		var i int
+		err = rows.Scan(&i)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, i)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,49 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/article"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_InTx(t *testing.T) {
+	ctx := context.TODO()
+
+	s, truncate := TestStore(ctx, t, dbURL)
+	defer truncate(ctx, "article", "article_link")
+
+	errStop := errors.New("stop")
+	var rolledBack article.Article
+	err := s.InTx(ctx, func(tx *Store) error {
+		rolledBack = article.Article{Name: "rolled back"}
+		err := tx.Article().Create(ctx, &rolledBack)
+		require.NoError(t, err)
+
+		err = tx.Article().SetLinks(ctx, rolledBack.ID, []article.Link{{ArticleName: "other"}})
+		require.NoError(t, err)
+
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+
+	_, err = s.Article().GetByID(ctx, rolledBack.ID)
+	assert.Error(t, err)
+
+	var committed article.Article
+	err = s.InTx(ctx, func(tx *Store) error {
+		// Nested InTx joins transaction of outer one.
+		return tx.InTx(ctx, func(tx *Store) error {
+			committed = article.Article{Name: "committed"}
+			return tx.Article().Create(ctx, &committed)
+		})
+	})
+	require.NoError(t, err)
+
+	got, err := s.Article().GetByID(ctx, committed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "committed", got.Name)
+}
@@ -0,0 +1,48 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/tag"
+)
+
+type TagRepoPG struct {
+	db dbtx
+}
+
+func NewTagRepoPG(db dbtx) *TagRepoPG {
+	return &TagRepoPG{db: db}
+}
+
+// GetAll returns tags of articles and examples that start with prefix, with number of marked entities.
+// If published is true, only published articles and examples are counted.
+func (r *TagRepoPG) GetAll(ctx context.Context, prefix string, published bool) ([]tag.Tag, error) {
+	q := `
+	select t.tag, count(*) from (
+		select at.tag from article_tags at join article a on a.id = at.article_id
+		where a.deleted_at is null and (a.published or not $2)
+		union all
+		select et.tag from example_tags et join example e on e.id = et.example_id
+		where e.deleted_at is null and (e.published or not $2)
+	) t
+	where t.tag like $1 || '%' escape '\'
+	group by t.tag order by t.tag
+	`
+
+	rows, err := r.db.Query(ctx, q, escapeLike(prefix), published)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]tag.Tag, 0)
+	for rows.Next() {
+		t := tag.Tag{}
+		err = rows.Scan(&t.Name, &t.Count)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,43 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/tag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `50\% of a\_b \\ c`, escapeLike(`50% of a_b \ c`))
+}
+
+func TestSearch_Wildcards(t *testing.T) {
+	ctx := context.TODO()
+
+	s, truncate := TestStore(ctx, t, dbURL)
+	defer truncate(ctx, "article", "article_tags")
+
+	arts := []*article.Article{{Name: "snake_case"}, {Name: "snakeXcase"}, {Name: "100% coverage"}}
+	for _, a := range arts {
+		require.NoError(t, s.Article().Create(ctx, a))
+	}
+	require.NoError(t, s.Article().SetTags(ctx, arts[0].ID, []string{"go_1", "go2"}))
+	require.NoError(t, s.Article().SetTags(ctx, arts[1].ID, []string{"gox1"}))
+
+	found, err := s.Article().Search(ctx, "e_c", "")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "snake_case", found[0].Name)
+
+	found, err = s.Article().Search(ctx, "%", "")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "100% coverage", found[0].Name)
+
+	tags, err := s.Tag().GetAll(ctx, "go_", false)
+	require.NoError(t, err)
+	assert.Equal(t, []tag.Tag{{Name: "go_1", Count: 1}}, tags)
+}
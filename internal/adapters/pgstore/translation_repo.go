@@ -0,0 +1,145 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/translation"
+	"errors"
+	"github.com/jackc/pgx/v5"
+)
+
+type TranslationRepoPG struct {
+	db dbtx
+}
+
+func NewTranslationRepoPG(db dbtx) *TranslationRepoPG {
+	return &TranslationRepoPG{db: db}
+}
+
+// Get returns translation of article to locale with translated descriptions of its examples.
+func (r *TranslationRepoPG) Get(ctx context.Context, artID int, locale string) (*translation.Translation, error) {
+	q := `select t.article_id, t.locale, t.name, t.description, t.source_version, t.author, t.updated_at,
+			t.published from article_translation t where t.article_id = $1 and t.locale = $2`
+
+	var t translation.Translation
+	err := r.db.QueryRow(ctx, q, artID, locale).Scan(&t.ArticleID, &t.Locale, &t.Name, &t.Description,
+		&t.SourceVersion, &t.Author, &t.UpdatedAt, &t.Published)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, translation.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	q = `select et.example_id, et.description, et.source_version from example_translation et
+			join article_examples ae on ae.example_id = et.example_id
+			where ae.article_id = $1 and et.locale = $2`
+
+	rows, err := r.db.Query(ctx, q, artID, locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	t.Examples = make(map[int]translation.Example)
+	for rows.Next() {
+		var e translation.Example
+		err = rows.Scan(&e.ExampleID, &e.Description, &e.SourceVersion)
+		if err != nil {
+			return nil, err
+		}
+		t.Examples[e.ExampleID] = e
+	}
+
+	return &t, rows.Err()
+}
+
+// GetLocales returns locales article is translated to, ordered by name. Locales of unpublished
+// translations are returned only if withDrafts is set.
+func (r *TranslationRepoPG) GetLocales(ctx context.Context, artID int, withDrafts bool) ([]string, error) {
+	q := `select t.locale from article_translation t where t.article_id = $1 and ($2 or t.published)
+			order by t.locale`
+	return queryStrings(ctx, r.db, q, artID, withDrafts)
+}
+
+// Save creates or replaces translation of article and translated descriptions of its examples, saved
+// translation is unpublished. Example translations with empty description are deleted.
+func (r *TranslationRepoPG) Save(ctx context.Context, t *translation.Translation) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	q := `insert into article_translation(article_id, locale, name, description, source_version, author)
+			values($1, $2, $3, $4, $5, $6)
+			on conflict (article_id, locale) do update set name = excluded.name,
+			description = excluded.description, source_version = excluded.source_version,
+			author = excluded.author, updated_at = now(), published = false
+			returning updated_at, published`
+	err = tx.QueryRow(ctx, q, t.ArticleID, t.Locale, t.Name, t.Description, t.SourceVersion, t.Author).
+		Scan(&t.UpdatedAt, &t.Published)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range t.Examples {
+		if e.Description == "" {
+			q = "delete from example_translation where example_id = $1 and locale = $2"
+			_, err = tx.Exec(ctx, q, e.ExampleID, t.Locale)
+		} else {
+			q = `insert into example_translation(example_id, locale, description, source_version)
+					values($1, $2, $3, $4)
+					on conflict (example_id, locale) do update set description = excluded.description,
+					source_version = excluded.source_version`
+			_, err = tx.Exec(ctx, q, e.ExampleID, t.Locale, e.Description, e.SourceVersion)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Publish makes translation of article to locale visible to readers.
+func (r *TranslationRepoPG) Publish(ctx context.Context, artID int, locale string) error {
+	q := "update article_translation set published = true where article_id = $1 and locale = $2"
+	commandTag, err := r.db.Exec(ctx, q, artID, locale)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return translation.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes translation of article and translated descriptions of its examples.
+func (r *TranslationRepoPG) Delete(ctx context.Context, artID int, locale string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	q := `delete from example_translation et using article_examples ae
+			where ae.example_id = et.example_id and ae.article_id = $1 and et.locale = $2`
+	_, err = tx.Exec(ctx, q, artID, locale)
+	if err != nil {
+		return err
+	}
+
+	q = "delete from article_translation where article_id = $1 and locale = $2"
+	commandTag, err := tx.Exec(ctx, q, artID, locale)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return translation.ErrNotFound
+	}
+
+	return tx.Commit(ctx)
+}
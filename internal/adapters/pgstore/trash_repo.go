@@ -0,0 +1,75 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/trash"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+type TrashRepoPG struct {
+	db dbtx
+}
+
+func NewTrashRepoPG(db dbtx) *TrashRepoPG {
+	return &TrashRepoPG{db: db}
+}
+
+const trashItems = `
+	select 'documentation' as entity_type, d.id, d.name, d.deleted_at from documentation d where d.deleted_at is not null
+	union all
+	select 'article', a.id, a.name, a.deleted_at from article a where a.deleted_at is not null
+	union all
+	select 'example', e.id, e.name, e.deleted_at from example e where e.deleted_at is not null
+	`
+
+// GetAll returns deleted documentations, articles and examples, recently deleted first.
+func (r *TrashRepoPG) GetAll(ctx context.Context) ([]trash.Item, error) {
+	q := `select * from (` + trashItems + `) i order by i.deleted_at desc, i.entity_type, i.id`
+
+	return r.query(ctx, q)
+}
+
+// GetDeletedBefore returns items deleted before t.
+func (r *TrashRepoPG) GetDeletedBefore(ctx context.Context, t time.Time) ([]trash.Item, error) {
+	q := `select * from (` + trashItems + `) i where i.deleted_at < $1 order by i.deleted_at`
+
+	return r.query(ctx, q, t)
+}
+
+// Get returns deleted entity. It returns trash.ErrNotInTrash if entity doesn't exist or isn't deleted.
+func (r *TrashRepoPG) Get(ctx context.Context, entityType string, id int) (*trash.Item, error) {
+	q := `select * from (` + trashItems + `) i where i.entity_type = $1 and i.id = $2`
+
+	var i trash.Item
+	err := r.db.QueryRow(ctx, q, entityType, id).Scan(&i.EntityType, &i.ID, &i.Name, &i.DeletedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, trash.ErrNotInTrash
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+func (r *TrashRepoPG) query(ctx context.Context, q string, args ...any) ([]trash.Item, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]trash.Item, 0)
+	for rows.Next() {
+		i := trash.Item{}
+		err = rows.Scan(&i.EntityType, &i.ID, &i.Name, &i.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, i)
+	}
+
+	return res, nil
+}
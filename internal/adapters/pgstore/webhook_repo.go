@@ -0,0 +1,197 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/event"
+	"documentation-mini-app/internal/domain/webhook"
+	"encoding/json"
+	"errors"
+	"github.com/jackc/pgx/v5"
+	"time"
+)
+
+type WebhookRepoPG struct {
+	db dbtx
+}
+
+func NewWebhookRepoPG(db dbtx) *WebhookRepoPG {
+	return &WebhookRepoPG{db: db}
+}
+
+const webhookColumns = `w.id, w.documentation_id, w.url, w.secret, w.events, w.created_at`
+
+const deliveryColumns = `d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+	d.last_error, d.response_code, d.created_at, d.delivered_at`
+
+func (r *WebhookRepoPG) Create(ctx context.Context, w *webhook.Webhook) error {
+	q := `insert into webhook(documentation_id, url, secret, events) values($1, $2, $3, $4) returning id, created_at`
+	return r.db.QueryRow(ctx, q, w.DocID, w.URL, w.Secret, w.Events).Scan(&w.ID, &w.CreatedAt)
+}
+
+func (r *WebhookRepoPG) GetByID(ctx context.Context, id int) (*webhook.Webhook, error) {
+	q := `select ` + webhookColumns + ` from webhook w where w.id = $1`
+
+	var w webhook.Webhook
+	err := r.db.QueryRow(ctx, q, id).Scan(&w.ID, &w.DocID, &w.URL, &w.Secret, &w.Events, &w.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, webhook.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &w, nil
+}
+
+func (r *WebhookRepoPG) GetByDocID(ctx context.Context, docID int) ([]webhook.Webhook, error) {
+	q := `select ` + webhookColumns + ` from webhook w where w.documentation_id = $1 order by w.id`
+
+	rows, err := r.db.Query(ctx, q, docID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]webhook.Webhook, 0)
+	for rows.Next() {
+		var w webhook.Webhook
+		err = rows.Scan(&w.ID, &w.DocID, &w.URL, &w.Secret, &w.Events, &w.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, w)
+	}
+
+	return res, rows.Err()
+}
+
+// Delete deletes webhook with its delivery log.
+func (r *WebhookRepoPG) Delete(ctx context.Context, id int) error {
+	q := "delete from webhook where id = $1"
+	commandTag, err := r.db.Exec(ctx, q, id)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return webhook.ErrNotFound
+	}
+
+	return nil
+}
+
+// Enqueue puts change to outbox of every webhook of affected documentations subscribed to it.
+func (r *WebhookRepoPG) Enqueue(ctx context.Context, c event.Change) error {
+	if len(c.DocIDs) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer rollback(ctx, tx)
+
+	q := `select w.id, w.documentation_id from webhook w where $1 = any(w.events) and w.documentation_id = any($2)`
+	rows, err := tx.Query(ctx, q, c.Type, c.DocIDs)
+	if err != nil {
+		return err
+	}
+
+	type target struct{ webhookID, docID int }
+	targets := make([]target, 0)
+	for rows.Next() {
+		var t target
+		err = rows.Scan(&t.webhookID, &t.docID)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		targets = append(targets, t)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return rows.Err()
+	}
+
+	for _, t := range targets {
+		payload, err := json.Marshal(webhook.NewPayload(c, t.docID))
+		if err != nil {
+			return err
+		}
+
+		q = `insert into webhook_delivery(webhook_id, event, payload, status) values($1, $2, $3, $4)`
+		_, err = tx.Exec(ctx, q, t.webhookID, c.Type, payload, webhook.StatusPending)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ClaimDue returns up to limit pending deliveries which time has come. Claimed deliveries are postponed
+// by lease so other workers don't send them at the same time.
+func (r *WebhookRepoPG) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	q := `update webhook_delivery d set next_attempt_at = now() + $3::interval
+			where d.id in (select p.id from webhook_delivery p
+				where p.status = $1 and p.next_attempt_at <= now()
+				order by p.next_attempt_at limit $2 for update skip locked)
+			returning ` + deliveryColumns
+
+	return r.queryDeliveries(ctx, q, webhook.StatusPending, limit, lease)
+}
+
+// SaveAttempt writes result of delivery attempt.
+func (r *WebhookRepoPG) SaveAttempt(ctx context.Context, d *webhook.Delivery) error {
+	q := `update webhook_delivery d set status = $1, attempts = $2, next_attempt_at = $3, last_error = $4,
+			response_code = $5, delivered_at = $6 where d.id = $7`
+	_, err := r.db.Exec(ctx, q, d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.ResponseCode, d.DeliveredAt,
+		d.ID)
+
+	return err
+}
+
+// GetDeliveries returns last limit deliveries of webhook, recent first.
+func (r *WebhookRepoPG) GetDeliveries(ctx context.Context, webhookID int, limit int) ([]webhook.Delivery, error) {
+	q := `select ` + deliveryColumns + ` from webhook_delivery d where d.webhook_id = $1 order by d.id desc limit $2`
+
+	return r.queryDeliveries(ctx, q, webhookID, limit)
+}
+
+// Retry queues failed delivery of webhook again with new attempts.
+func (r *WebhookRepoPG) Retry(ctx context.Context, webhookID int, deliveryID int64) error {
+	q := `update webhook_delivery d set status = $1, attempts = 0, next_attempt_at = now()
+			where d.id = $2 and d.webhook_id = $3 and d.status = $4`
+	commandTag, err := r.db.Exec(ctx, q, webhook.StatusPending, deliveryID, webhookID, webhook.StatusFailed)
+	if err != nil {
+		return err
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return webhook.ErrNotRetryable
+	}
+
+	return nil
+}
+
+func (r *WebhookRepoPG) queryDeliveries(ctx context.Context, q string, args ...any) ([]webhook.Delivery, error) {
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]webhook.Delivery, 0)
+	for rows.Next() {
+		var d webhook.Delivery
+		err = rows.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&d.LastError, &d.ResponseCode, &d.CreatedAt, &d.DeliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+
+	return res, rows.Err()
+}
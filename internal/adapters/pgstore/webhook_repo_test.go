@@ -0,0 +1,93 @@
+package pgstore
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/event"
+	"documentation-mini-app/internal/domain/webhook"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookRepoPG_EmitInTx(t *testing.T) {
+	ctx := context.TODO()
+
+	s, truncate := TestStore(ctx, t, dbURL)
+	defer truncate(ctx, "documentation", "webhook", "webhook_delivery")
+
+	d := doc.Documentation{Name: "example"}
+	require.NoError(t, s.Doc().Create(ctx, &d))
+
+	w := webhook.Webhook{DocID: d.ID, URL: "https://example.com/hook", Secret: "s", Events: []string{event.DocUpdated}}
+	require.NoError(t, s.Webhook().Create(ctx, &w))
+
+	// Delivery is queued only when mutation is committed.
+	errStop := errors.New("stop")
+	err := s.InTx(ctx, func(tx *Store) error {
+		require.NoError(t, tx.Event().Emit(ctx, event.DocUpdated, d.ID))
+		return errStop
+	})
+	assert.ErrorIs(t, err, errStop)
+
+	deliveries, err := s.Webhook().GetDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	err = s.InTx(ctx, func(tx *Store) error {
+		return tx.Event().Emit(ctx, event.DocUpdated, d.ID)
+	})
+	require.NoError(t, err)
+
+	deliveries, err = s.Webhook().GetDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, event.DocUpdated, deliveries[0].Event)
+
+	// Claimed delivery is postponed by lease.
+	claimed, err := s.Webhook().ClaimDue(ctx, 1, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.True(t, claimed[0].NextAttemptAt.After(time.Now()))
+
+	claimed, err = s.Webhook().ClaimDue(ctx, 1, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+}
+
+func TestWebhookRepoPG_EmitDraft(t *testing.T) {
+	ctx := context.TODO()
+
+	s, truncate := TestStore(ctx, t, dbURL)
+	defer truncate(ctx, "documentation", "article", "webhook", "webhook_delivery")
+
+	d := doc.Documentation{Name: "example"}
+	require.NoError(t, s.Doc().Create(ctx, &d))
+
+	w := webhook.Webhook{DocID: d.ID, URL: "https://example.com/hook", Secret: "s",
+		Events: []string{event.ArticleAdded, event.ArticlePublished}}
+	require.NoError(t, s.Webhook().Create(ctx, &w))
+
+	a := article.Article{Name: "draft"}
+	require.NoError(t, s.Article().Create(ctx, &a))
+	require.NoError(t, s.Article().AddToDoc(ctx, a.ID, d.ID))
+
+	// Draft is not sent to webhooks until it is published.
+	require.NoError(t, s.Event().Emit(ctx, event.ArticleAdded, a.ID))
+
+	deliveries, err := s.Webhook().GetDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+
+	require.NoError(t, s.Article().SetPublished(ctx, a.ID, true))
+	require.NoError(t, s.Event().Emit(ctx, event.ArticlePublished, a.ID))
+
+	deliveries, err = s.Webhook().GetDeliveries(ctx, w.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, event.ArticlePublished, deliveries[0].Event)
+}
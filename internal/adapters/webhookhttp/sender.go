@@ -0,0 +1,54 @@
+package webhookhttp
+
+import (
+	"bytes"
+	"context"
+	"documentation-mini-app/internal/domain/webhook"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxErrorBody is number of bytes of response body kept as error of failed delivery.
+const maxErrorBody = 512
+
+// Sender posts signed payloads of deliveries to webhooks.
+type Sender struct {
+	Client *http.Client
+}
+
+func New(timeout time.Duration) *Sender {
+	return &Sender{Client: &http.Client{Timeout: timeout}}
+}
+
+// Send posts payload of delivery to webhook URL and returns response status code. Code is zero if request
+// failed before response. Any status except 2xx is error.
+func (s *Sender) Send(ctx context.Context, w *webhook.Webhook, d *webhook.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "documentation-mini-app-webhook")
+	req.Header.Set(webhook.HeaderEvent, d.Event)
+	req.Header.Set(webhook.HeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(w.Secret, d.Payload))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("webhook responded %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
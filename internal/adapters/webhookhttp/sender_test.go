@@ -0,0 +1,46 @@
+package webhookhttp
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/event"
+	"documentation-mini-app/internal/domain/webhook"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	status := http.StatusNoContent
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("try later"))
+	}))
+	defer receiver.Close()
+
+	w := &webhook.Webhook{URL: receiver.URL, Secret: "secret"}
+	d := &webhook.Delivery{ID: 7, Event: event.ArticleUpdated, Payload: []byte(`{"entity_id":1}`)}
+	s := New(time.Second)
+
+	code, err := s.Send(context.Background(), w, d)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, code)
+	assert.Equal(t, d.Payload, body)
+	assert.Equal(t, "7", got.Header.Get(webhook.HeaderDelivery))
+	assert.Equal(t, event.ArticleUpdated, got.Header.Get(webhook.HeaderEvent))
+	assert.True(t, webhook.Verify("secret", body, got.Header.Get(webhook.HeaderSignature)))
+
+	status = http.StatusServiceUnavailable
+	code, err = s.Send(context.Background(), w, d)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.ErrorContains(t, err, "try later")
+}
@@ -0,0 +1,72 @@
+package codefmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strings"
+)
+
+// SyntaxError is returned when code can't be parsed by the formatter of its language.
+type SyntaxError struct {
+	Lang string
+	Err  error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v code: %v", e.Lang, e.Err)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+type formatFunc func(code string) (string, error)
+
+var formatters = map[string]formatFunc{
+	"go":     formatGo,
+	"golang": formatGo,
+	"json":   formatJSON,
+}
+
+// Format formats code according to highlight language lang.
+// Languages without own formatter only get \r\n line endings converted to \n, so contents of
+// string literals are never changed.
+func Format(lang string, code string) (string, error) {
+	code = strings.ReplaceAll(code, "\r\n", "\n")
+	if strings.TrimSpace(code) == "" {
+		return "", nil
+	}
+
+	f, ok := formatters[strings.ToLower(strings.TrimSpace(lang))]
+	if !ok {
+		return code, nil
+	}
+
+	res, err := f(code)
+	if err != nil {
+		return "", &SyntaxError{Lang: lang, Err: err}
+	}
+
+	return strings.TrimRight(res, "\n"), nil
+}
+
+func formatGo(code string) (string, error) {
+	res, err := format.Source([]byte(code))
+	if err != nil {
+		return "", err
+	}
+
+	return string(res), nil
+}
+
+func formatJSON(code string) (string, error) {
+	var b bytes.Buffer
+	err := json.Indent(&b, []byte(code), "", "    ")
+	if err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
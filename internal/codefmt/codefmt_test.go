@@ -0,0 +1,67 @@
+package codefmt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		code string
+		want string
+	}{
+		{
+			name: "go",
+			lang: "go",
+			code: "package main\r\nfunc main(){\nprintln( 1 )\n}\n\n",
+			want: "package main\n\nfunc main() {\n\tprintln(1)\n}",
+		},
+		{
+			name: "json",
+			lang: "JSON",
+			code: `{"a":1,"b":[1,2]}`,
+			want: "{\n    \"a\": 1,\n    \"b\": [\n        1,\n        2\n    ]\n}",
+		},
+		{
+			name: "go raw string",
+			lang: "go",
+			code: "package main\n\nvar s = `a  \n\tb\t\n`\n",
+			want: "package main\n\nvar s = `a  \n\tb\t\n`",
+		},
+		{
+			name: "unknown language",
+			lang: "python",
+			code: "\r\nprint(\"\"\"a  \r\nb\rc\"\"\")   \r\n",
+			want: "\nprint(\"\"\"a  \nb\rc\"\"\")   \n",
+		},
+		{
+			name: "empty",
+			lang: "go",
+			code: " \n ",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format(tt.lang, tt.code)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormat_SyntaxError(t *testing.T) {
+	_, err := Format("go", "func main() {")
+
+	var syntaxErr *SyntaxError
+	assert.True(t, errors.As(err, &syntaxErr))
+	assert.Equal(t, "go", syntaxErr.Lang)
+
+	_, err = Format("json", "{")
+	assert.Error(t, err)
+}
@@ -4,10 +4,29 @@ import (
 	"encoding/json"
 	"io"
 	"log"
+	"os"
 )
 
 type Config struct {
 	Addr string `json:"addr"`
+	// BaseURL is external URL of the site, e.g. https://docs.example.com. It is used to recognize own links.
+	BaseURL string `json:"base_url"`
+	// TrashRetentionDays is number of days after which deleted items are purged. Zero disables purge.
+	TrashRetentionDays int `json:"trash_retention_days"`
+	// OverrideDir is optional directory with templates and static subdirectories. Files there replace
+	// embedded templates and assets with the same path, e.g. templates/docs/get_doc.html.
+	OverrideDir string `json:"override_dir"`
+	// Dev enables development mode: templates are read from templates directory of working directory
+	// and reloaded when changed, so server runs from repository root without restarts.
+	Dev bool `json:"dev"`
+	// Users maps names of editors to bcrypt hashes of their passwords, e.g. made by htpasswd -nbB name password.
+	Users map[string]string `json:"users"`
+	// SessionKey is secret signing session cookies. Random key is used if it is empty, then editors have to log in
+	// again after restart.
+	SessionKey string `json:"session_key"`
+	// TrustedProxies are addresses or CIDR ranges of authenticating reverse proxies. X-Remote-User header is
+	// accepted only from them, the header is ignored if the list is empty.
+	TrustedProxies []string `json:"trusted_proxies"`
 }
 
 func Parse(r io.Reader) *Config {
@@ -19,3 +38,20 @@ func Parse(r io.Reader) *Config {
 
 	return &config
 }
+
+// ParseFile reads config from file at path.
+func ParseFile(path string) *Config {
+	configFile, err := os.Open(path)
+	if err != nil {
+		log.Panicf("config file open: %v\n", err)
+	}
+
+	defer func(configFile *os.File) {
+		err := configFile.Close()
+		if err != nil {
+			log.Panicf("file close: %v\n", err)
+		}
+	}(configFile)
+
+	return Parse(configFile)
+}
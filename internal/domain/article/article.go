@@ -2,11 +2,52 @@ package article
 
 import (
 	"documentation-mini-app/internal/domain/example"
+	"errors"
+)
+
+var (
+	ErrNotFound = errors.New("article not found")
+	// ErrConflict is returned when article was changed by someone else since editor opened it.
+	ErrConflict = errors.New("article was changed concurrently")
 )
 
 type Article struct {
-	ID          int
-	Name        string
+	ID   int
+	Name string
+	// Slug is unique human-readable identifier made from name, it is used in URLs.
+	Slug        string
 	Description string
-	Examples    []example.Example
+	// Published is false for new article until its first revision is published.
+	Published bool
+	// Version is incremented on every saved edit, it is used to detect concurrent edits.
+	Version   int
+	Tags      []string
+	Examples  []example.Example
+	Links     []Link
+	Backlinks []Article
+}
+
+// FilterPublished returns published articles with their published examples. It is used to hide drafts from readers.
+func FilterPublished(arts []Article) []Article {
+	res := make([]Article, 0, len(arts))
+	for _, a := range arts {
+		if a.Published {
+			a.Examples = example.FilterPublished(a.Examples)
+			res = append(res, a)
+		}
+	}
+
+	return res
+}
+
+// FilterByIDs returns articles which ids are in set.
+func FilterByIDs(arts []Article, ids map[int]bool) []Article {
+	res := make([]Article, 0, len(arts))
+	for _, a := range arts {
+		if ids[a.ID] {
+			res = append(res, a)
+		}
+	}
+
+	return res
 }
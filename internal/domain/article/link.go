@@ -0,0 +1,92 @@
+package article
+
+import (
+	"regexp"
+	"strings"
+)
+
+var linkRe = regexp.MustCompile(`\[\[([^\[\]]+)]]`)
+
+// Link is reference to other article written as [[Article Name]] or [[Documentation/Article Name]].
+// Slash in names is escaped by backslash, e.g. [[TCP\/IP]] or [[Networks/TCP\/IP]].
+// ArticleID is zero if reference is broken.
+type Link struct {
+	DocName     string
+	ArticleName string
+	ArticleID   int
+}
+
+// ParseLink parses reference text between double brackets.
+func ParseLink(ref string) Link {
+	docName, artName, found := cutUnescaped(ref, '/')
+	if !found {
+		return Link{ArticleName: unescapeName(ref)}
+	}
+
+	return Link{
+		DocName:     unescapeName(docName),
+		ArticleName: unescapeName(artName),
+	}
+}
+
+// Ref returns reference text of link as it is written between double brackets.
+func (l Link) Ref() string {
+	ref := strings.ReplaceAll(l.ArticleName, "/", `\/`)
+	if l.DocName != "" {
+		ref = strings.ReplaceAll(l.DocName, "/", `\/`) + "/" + ref
+	}
+
+	return ref
+}
+
+// cutUnescaped slices s around the first sep not preceded by backslash.
+func cutUnescaped(s string, sep byte) (before, after string, found bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+
+	return s, "", false
+}
+
+// unescapeName returns name with escaped slashes replaced by slashes and trimmed spaces.
+func unescapeName(name string) string {
+	return strings.TrimSpace(strings.ReplaceAll(name, `\/`, "/"))
+}
+
+// ParseLinks returns unique references in text in order of their appearance.
+func ParseLinks(text string) []Link {
+	res := make([]Link, 0)
+	seen := make(map[Link]bool)
+
+	for _, m := range linkRe.FindAllStringSubmatch(text, -1) {
+		l := ParseLink(m[1])
+		if l.ArticleName == "" || seen[l] {
+			continue
+		}
+		seen[l] = true
+
+		res = append(res, l)
+	}
+
+	return res
+}
+
+// RenderLinks joins results of plain called for text between references and link called for every reference.
+func RenderLinks(text string, plain func(s string) string, link func(raw string, l Link) string) string {
+	var b strings.Builder
+
+	last := 0
+	for _, m := range linkRe.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(plain(text[last:m[0]]))
+		b.WriteString(link(text[m[0]:m[1]], ParseLink(text[m[2]:m[3]])))
+		last = m[1]
+	}
+	b.WriteString(plain(text[last:]))
+
+	return b.String()
+}
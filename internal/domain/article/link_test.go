@@ -0,0 +1,38 @@
+package article
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinks(t *testing.T) {
+	text := "See [[Goroutines]] and [[Go/ Channels ]], again [[Goroutines]]. Not a link: [[]] [x]"
+
+	links := ParseLinks(text)
+
+	assert.Equal(t, []Link{
+		{ArticleName: "Goroutines"},
+		{DocName: "Go", ArticleName: "Channels"},
+	}, links)
+}
+
+func TestParseLink_EscapedSlash(t *testing.T) {
+	assert.Equal(t, Link{ArticleName: "TCP/IP"}, ParseLink(`TCP\/IP`))
+	assert.Equal(t, Link{DocName: "Networks", ArticleName: "TCP/IP"}, ParseLink(`Networks/ TCP\/IP`))
+	assert.Equal(t, Link{DocName: "A/B", ArticleName: "C/D"}, ParseLink(`A\/B/C/D`))
+	assert.Equal(t, Link{ArticleName: `a\`}, ParseLink(`a\`))
+
+	for _, ref := range []string{"Go", "Go/Channels", `TCP\/IP`, `A\/B/C\/D`} {
+		assert.Equal(t, ref, ParseLink(ref).Ref())
+	}
+}
+
+func TestRenderLinks(t *testing.T) {
+	res := RenderLinks("a [[Doc/B]] c", strings.ToUpper, func(raw string, l Link) string {
+		return l.DocName + ":" + l.ArticleName
+	})
+
+	assert.Equal(t, "A Doc:B C", res)
+}
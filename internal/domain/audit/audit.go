@@ -0,0 +1,129 @@
+package audit
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/user"
+	"encoding/json"
+	"time"
+)
+
+// Actions of audit entries.
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+	ActionLink    = "link"
+	ActionUnlink  = "unlink"
+	ActionSubmit  = "submit"
+	ActionApprove = "approve"
+	ActionReject  = "reject"
+	ActionPublish = "publish"
+	ActionResolve = "resolve"
+	ActionReopen  = "reopen"
+)
+
+// Entity types of audit entries.
+const (
+	EntityDoc         = "documentation"
+	EntityArticle     = "article"
+	EntityExample     = "example"
+	EntityVersion     = "version"
+	EntityRevision    = "revision"
+	EntityThread      = "thread"
+	EntityComment     = "comment"
+	EntityWebhook     = "webhook"
+	EntityTranslation = "translation"
+)
+
+// Actions lists all actions, EntityTypes lists all entity types. They are used to filter entries.
+var (
+	Actions = []string{ActionCreate, ActionUpdate, ActionDelete, ActionRestore, ActionPurge, ActionLink,
+		ActionUnlink, ActionSubmit, ActionApprove, ActionReject, ActionPublish, ActionResolve, ActionReopen}
+	EntityTypes = []string{EntityDoc, EntityArticle, EntityExample, EntityVersion, EntityRevision, EntityThread,
+		EntityComment, EntityWebhook, EntityTranslation}
+)
+
+// ActorSystem is actor of changes made without user, e.g. automatic purge of trash.
+const ActorSystem = "system"
+
+// Entry is record about one change of entity. Before and After are JSON states of entity or details of change.
+type Entry struct {
+	ID         int64           `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   int             `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  string          `json:"request_id"`
+	IP         string          `json:"ip"`
+}
+
+// Filter selects audit entries. Zero fields match any entry.
+type Filter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   int
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+// Request describes HTTP request in which change is made.
+type Request struct {
+	ID string
+	IP string
+}
+
+type requestKey struct{}
+
+// NewContext returns context with request.
+func NewContext(ctx context.Context, r Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, r)
+}
+
+// RequestFromContext returns request of context. It is empty for changes made outside HTTP requests.
+func RequestFromContext(ctx context.Context) Request {
+	r, _ := ctx.Value(requestKey{}).(Request)
+	return r
+}
+
+// New returns entry about change made in ctx. Nil before or after means that entity had no state before
+// creation or after deletion.
+func New(ctx context.Context, action, entityType string, entityID int, before, after any) (Entry, error) {
+	e := Entry{
+		Actor:      ActorSystem,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+
+	if u, ok := user.FromContext(ctx); ok {
+		e.Actor = u.Name
+	}
+
+	r := RequestFromContext(ctx)
+	e.RequestID = r.ID
+	e.IP = r.IP
+
+	var err error
+	if before != nil {
+		e.Before, err = json.Marshal(before)
+		if err != nil {
+			return Entry{}, err
+		}
+	}
+
+	if after != nil {
+		e.After, err = json.Marshal(after)
+		if err != nil {
+			return Entry{}, err
+		}
+	}
+
+	return e, nil
+}
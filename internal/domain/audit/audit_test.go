@@ -0,0 +1,26 @@
+package audit
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/user"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	ctx := NewContext(context.Background(), Request{ID: "req-1", IP: "10.0.0.1"})
+
+	e, err := New(ctx, ActionDelete, EntityArticle, 3, map[string]string{"name": "a"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ActorSystem, e.Actor)
+	assert.Equal(t, "req-1", e.RequestID)
+	assert.JSONEq(t, `{"name":"a"}`, string(e.Before))
+	assert.Nil(t, e.After)
+
+	e, err = New(user.NewContext(ctx, &user.User{Name: "bob"}), ActionCreate, EntityArticle, 3, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", e.Actor)
+	assert.Equal(t, "1", string(e.After))
+}
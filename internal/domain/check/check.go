@@ -0,0 +1,43 @@
+package check
+
+const (
+	KindOrphanExample     = "orphan_example"
+	KindArticleWithoutDoc = "article_without_doc"
+	KindDuplicateArticle  = "duplicate_article_name"
+	KindEmptyDescription  = "empty_description"
+	KindExampleNoOutput   = "example_without_output"
+	KindBrokenLink        = "broken_link"
+)
+
+const (
+	EntityDoc     = "documentation"
+	EntityArticle = "article"
+	EntityExample = "example"
+)
+
+// Issue is one found consistency problem of entity.
+type Issue struct {
+	Kind       string `json:"kind"`
+	EntityType string `json:"entity_type"`
+	EntityID   int    `json:"entity_id"`
+	EntityName string `json:"entity_name"`
+	Message    string `json:"message"`
+}
+
+type Report struct {
+	Issues []Issue        `json:"issues"`
+	Counts map[string]int `json:"counts"`
+}
+
+func (r *Report) Add(issue Issue) {
+	if r.Counts == nil {
+		r.Counts = make(map[string]int)
+	}
+
+	r.Issues = append(r.Issues, issue)
+	r.Counts[issue.Kind]++
+}
+
+func (r *Report) OK() bool {
+	return len(r.Issues) == 0
+}
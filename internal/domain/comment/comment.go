@@ -0,0 +1,36 @@
+package comment
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrEmptyBody    = errors.New("comment can't be empty")
+	ErrAlienExample = errors.New("example doesn't belong to article of thread")
+)
+
+// Thread is discussion attached to article or to one of its examples.
+type Thread struct {
+	ID        int
+	ArticleID int
+	// ExampleID is zero if thread is attached to article itself.
+	ExampleID  int
+	Resolved   bool
+	ResolvedBy string
+	Author     string
+	CreatedAt  time.Time
+	Comments   []Comment
+
+	// ArticleName and ExampleName are set in thread lists of documentation.
+	ArticleName string
+	ExampleName string
+}
+
+type Comment struct {
+	ID        int
+	ThreadID  int
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
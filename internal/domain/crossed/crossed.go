@@ -1,6 +1,179 @@
 package crossed
 
+import (
+	"sort"
+	"strings"
+)
+
+// Sort orders of matrix rows and columns.
+const (
+	// SortName orders documentations and articles by name.
+	SortName = "name"
+	// SortTotal orders documentations by count of articles and articles by count of documentations.
+	SortTotal = "total"
+)
+
+// Crossed is matrix of articles included into documentations.
 type Crossed struct {
-	Map          map[string]map[string]int
-	ArticleNames []string
+	Docs     []Entry
+	Articles []Entry
+	// Map is count of inclusions of article into documentation by documentation ID and article ID.
+	Map map[int]map[int]int
+}
+
+// Entry is documentation or article identified by ID, Name is only displayed.
+type Entry struct {
+	ID   int
+	Name string
+}
+
+// Row is counts of articles in one documentation in order of Articles.
+type Row struct {
+	Doc    Entry
+	Counts []int
+	Total  int
+}
+
+// Filter selects part of matrix.
+type Filter struct {
+	// DocIDs are documentations to keep, all documentations are kept if it is empty.
+	// Articles not included into any of them are dropped.
+	DocIDs []int
+	// Shared keeps only articles included into more than one documentation.
+	Shared bool
+	Sort   string
+}
+
+// Count returns how many times article is included into documentation.
+func (c Crossed) Count(docID int, artID int) int {
+	return c.Map[docID][artID]
+}
+
+// Rows returns matrix rows with total count of articles in each documentation.
+func (c Crossed) Rows() []Row {
+	rows := make([]Row, 0, len(c.Docs))
+	for _, d := range c.Docs {
+		row := Row{Doc: d, Counts: make([]int, len(c.Articles))}
+		for i, a := range c.Articles {
+			row.Counts[i] = c.Count(d.ID, a.ID)
+			row.Total += row.Counts[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// ArticleTotals returns how many times each article is included into documentations in order of Articles.
+func (c Crossed) ArticleTotals() []int {
+	totals := make([]int, len(c.Articles))
+	for _, d := range c.Docs {
+		for i, a := range c.Articles {
+			totals[i] += c.Count(d.ID, a.ID)
+		}
+	}
+
+	return totals
+}
+
+// Total returns count of all inclusions of articles into documentations.
+func (c Crossed) Total() int {
+	total := 0
+	for _, t := range c.ArticleTotals() {
+		total += t
+	}
+
+	return total
+}
+
+// Apply returns part of matrix selected by filter in its sort order.
+func (c Crossed) Apply(f Filter) Crossed {
+	res := Crossed{Docs: c.Docs, Articles: c.Articles, Map: c.Map}
+
+	if len(f.DocIDs) > 0 {
+		keep := make(map[int]bool, len(f.DocIDs))
+		for _, id := range f.DocIDs {
+			keep[id] = true
+		}
+
+		res.Docs = make([]Entry, 0, len(f.DocIDs))
+		for _, d := range c.Docs {
+			if keep[d.ID] {
+				res.Docs = append(res.Docs, d)
+			}
+		}
+	}
+
+	if len(f.DocIDs) > 0 || f.Shared {
+		least := 1
+		if f.Shared {
+			least = 2
+		}
+
+		counts := res.docCounts()
+		res.Articles = make([]Entry, 0, len(c.Articles))
+		for i, total := range counts {
+			if total >= least {
+				res.Articles = append(res.Articles, c.Articles[i])
+			}
+		}
+	}
+
+	res.sort(f.Sort)
+
+	return res
+}
+
+// docCounts returns count of documentations including each article in order of Articles.
+func (c Crossed) docCounts() []int {
+	counts := make([]int, len(c.Articles))
+	for _, d := range c.Docs {
+		for i, a := range c.Articles {
+			if c.Count(d.ID, a.ID) > 0 {
+				counts[i]++
+			}
+		}
+	}
+
+	return counts
+}
+
+func (c *Crossed) sort(order string) {
+	docs := append([]Entry(nil), c.Docs...)
+	arts := append([]Entry(nil), c.Articles...)
+
+	if order == SortTotal {
+		docTotals := make(map[int]int, len(docs))
+		for _, r := range c.Rows() {
+			docTotals[r.Doc.ID] = r.Total
+		}
+
+		artTotals := make(map[int]int, len(arts))
+		for i, t := range c.ArticleTotals() {
+			artTotals[arts[i].ID] = t
+		}
+
+		sortEntries(docs, docTotals)
+		sortEntries(arts, artTotals)
+	} else {
+		sortEntries(docs, nil)
+		sortEntries(arts, nil)
+	}
+
+	c.Docs, c.Articles = docs, arts
+}
+
+// sortEntries sorts entries by totals descending, then by name and ID.
+func sortEntries(entries []Entry, totals map[int]int) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if totals[a.ID] != totals[b.ID] {
+			return totals[a.ID] > totals[b.ID]
+		}
+		if n := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); n != 0 {
+			return n < 0
+		}
+
+		return a.ID < b.ID
+	})
 }
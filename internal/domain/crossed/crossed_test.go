@@ -0,0 +1,56 @@
+package crossed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	docGo  = Entry{ID: 1, Name: "go"}
+	docSQL = Entry{ID: 2, Name: "sql"}
+	intro1 = Entry{ID: 10, Name: "intro"}
+	intro2 = Entry{ID: 11, Name: "intro"}
+	maps   = Entry{ID: 12, Name: "maps"}
+	orphan = Entry{ID: 13, Name: "orphan"}
+)
+
+func testCrossed() Crossed {
+	return Crossed{
+		Docs:     []Entry{docSQL, docGo},
+		Articles: []Entry{maps, intro2, orphan, intro1},
+		Map: map[int]map[int]int{
+			docGo.ID:  {intro1.ID: 1, maps.ID: 1},
+			docSQL.ID: {intro1.ID: 1, intro2.ID: 1},
+		},
+	}
+}
+
+func TestTotals(t *testing.T) {
+	c := testCrossed().Apply(Filter{})
+
+	assert.Equal(t, []Entry{docGo, docSQL}, c.Docs)
+	assert.Equal(t, []Entry{intro1, intro2, maps, orphan}, c.Articles)
+	assert.Equal(t, []Row{
+		{Doc: docGo, Counts: []int{1, 0, 1, 0}, Total: 2},
+		{Doc: docSQL, Counts: []int{1, 1, 0, 0}, Total: 2},
+	}, c.Rows())
+	assert.Equal(t, []int{2, 1, 1, 0}, c.ArticleTotals())
+	assert.Equal(t, 4, c.Total())
+}
+
+func TestApply(t *testing.T) {
+	c := testCrossed().Apply(Filter{Shared: true})
+	assert.Equal(t, []Entry{docGo, docSQL}, c.Docs)
+	assert.Equal(t, []Entry{intro1}, c.Articles)
+
+	c = testCrossed().Apply(Filter{DocIDs: []int{docSQL.ID}})
+	assert.Equal(t, []Entry{docSQL}, c.Docs)
+	assert.Equal(t, []Entry{intro1, intro2}, c.Articles)
+
+	c = testCrossed().Apply(Filter{DocIDs: []int{docSQL.ID}, Shared: true})
+	assert.Empty(t, c.Articles)
+
+	c = testCrossed().Apply(Filter{Sort: SortTotal})
+	assert.Equal(t, []Entry{intro1, intro2, maps, orphan}, c.Articles)
+}
@@ -0,0 +1,40 @@
+package doc
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/example"
+	"errors"
+)
+
+// Cascade modes of documentation deletion.
+const (
+	// CascadeUnlink deletes only documentation, its articles stay without documentation.
+	CascadeUnlink = "unlink"
+	// CascadeArticles deletes also articles that belong to no other documentation.
+	CascadeArticles = "articles"
+	// CascadeExamples deletes also examples of deleted articles that belong to no other article.
+	CascadeExamples = "examples"
+)
+
+var ErrUnknownCascade = errors.New("unknown cascade mode")
+
+// DeletePlan describes what is affected by deletion of documentation.
+type DeletePlan struct {
+	Doc *Documentation
+	// Exclusive are articles that belong only to documentation, they are deleted by CascadeArticles.
+	Exclusive []article.Article
+	// Shared are articles that belong to other documentations too, they are never deleted.
+	Shared []article.Article
+	// ExclusiveExamples are examples that belong only to exclusive articles, they are deleted by CascadeExamples.
+	ExclusiveExamples []example.Example
+}
+
+// ValidCascade reports whether cascade is known cascade mode.
+func ValidCascade(cascade string) bool {
+	switch cascade {
+	case CascadeUnlink, CascadeArticles, CascadeExamples:
+		return true
+	}
+
+	return false
+}
@@ -2,11 +2,23 @@ package doc
 
 import (
 	"documentation-mini-app/internal/domain/article"
+	"errors"
+)
+
+var (
+	ErrNotFound = errors.New("documentation not found")
+	// ErrConflict is returned when documentation was changed by someone else since editor opened it.
+	ErrConflict = errors.New("documentation was changed concurrently")
 )
 
 type Documentation struct {
-	ID                       int
-	Name                     string
+	ID   int
+	Name string
+	// Slug is unique human-readable identifier made from name, it is used in URLs.
+	Slug                     string
 	DefaultHighlightLanguage string
-	Articles                 []article.Article
+	// Version is incremented on every saved edit, it is used to detect concurrent edits.
+	Version  int
+	Articles []article.Article
+	Versions []Version
 }
@@ -0,0 +1,34 @@
+package doc
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"errors"
+	"time"
+)
+
+var (
+	ErrVersionExists      = errors.New("version with this name already exists")
+	ErrVersionNotFound    = errors.New("version not found")
+	ErrInvalidVersionName = errors.New("version name must be non-empty and can't contain slashes")
+)
+
+// Version is immutable named snapshot of documentation, e.g. v1 or v2.
+type Version struct {
+	ID        int
+	DocID     int
+	Name      string
+	CreatedAt time.Time
+	// Doc is snapshot of documentation with all its articles and examples. It is nil in version lists.
+	Doc *Documentation
+}
+
+// ArticleByID returns article of documentation with id.
+func (d *Documentation) ArticleByID(id int) (*article.Article, bool) {
+	for i := range d.Articles {
+		if d.Articles[i].ID == id {
+			return &d.Articles[i], true
+		}
+	}
+
+	return nil, false
+}
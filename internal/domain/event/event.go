@@ -0,0 +1,85 @@
+package event
+
+import (
+	"strings"
+	"time"
+)
+
+// Types of events about changes of documentation content. Type starts with type of changed entity.
+const (
+	DocUpdated       = "documentation.updated"
+	DocDeleted       = "documentation.deleted"
+	VersionPublished = "version.published"
+	ArticleAdded     = "article.added"
+	ArticleUpdated   = "article.updated"
+	ArticlePublished = "article.published"
+	ArticleDeleted   = "article.deleted"
+	ExampleAdded     = "example.added"
+	ExampleUpdated   = "example.updated"
+	ExamplePublished = "example.published"
+	ExampleDeleted   = "example.deleted"
+)
+
+// Types lists all event types.
+var Types = []string{DocUpdated, DocDeleted, VersionPublished,
+	ArticleAdded, ArticleUpdated, ArticlePublished, ArticleDeleted,
+	ExampleAdded, ExampleUpdated, ExamplePublished, ExampleDeleted}
+
+// Change is event emitted by usecases after change of entity. It is sent to webhooks and live pages
+// of documentations and articles that contain the entity.
+type Change struct {
+	Type       string    `json:"event"`
+	EntityType string    `json:"entity_type"`
+	EntityID   int       `json:"entity_id"`
+	DocIDs     []int     `json:"documentation_ids"`
+	ArticleIDs []int     `json:"article_ids"`
+	OccurredAt time.Time `json:"occurred_at"`
+	// Draft is set for change of unpublished article or example, it is shown only to editors
+	// and never sent to webhooks.
+	Draft bool `json:"draft,omitempty"`
+}
+
+// New returns change of entity without affected documentations and articles.
+func New(t string, entityID int) Change {
+	entityType, _, _ := strings.Cut(t, ".")
+
+	return Change{
+		Type:       t,
+		EntityType: entityType,
+		EntityID:   entityID,
+		DocIDs:     make([]int, 0),
+		ArticleIDs: make([]int, 0),
+		OccurredAt: time.Now(),
+	}
+}
+
+// Known reports whether t is event type.
+func Known(t string) bool {
+	for _, k := range Types {
+		if k == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AffectsDoc reports whether change is shown on page of documentation.
+func (c Change) AffectsDoc(docID int) bool {
+	return contains(c.DocIDs, docID)
+}
+
+// AffectsArticle reports whether change is shown on page of article.
+func (c Change) AffectsArticle(artID int) bool {
+	return contains(c.ArticleIDs, artID)
+}
+
+func contains(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+
+	return false
+}
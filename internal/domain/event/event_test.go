@@ -0,0 +1,18 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	c := New(ExampleUpdated, 4)
+	c.ArticleIDs = []int{2, 3}
+
+	assert.Equal(t, "example", c.EntityType)
+	assert.True(t, c.AffectsArticle(3))
+	assert.False(t, c.AffectsDoc(4))
+	assert.True(t, Known(VersionPublished))
+	assert.False(t, Known("example"))
+}
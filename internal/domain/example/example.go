@@ -1,5 +1,22 @@
 package example
 
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+var (
+	// ErrInvalidCode is returned when example code can't be parsed for its highlight language.
+	ErrInvalidCode = errors.New("invalid example code")
+	ErrNotFound    = errors.New("example not found")
+	// ErrConflict is returned when example was changed by someone else since editor opened it.
+	ErrConflict = errors.New("example was changed concurrently")
+	// ErrInvalidFiles is returned when files of multi-file example can't be saved or unpacked safely.
+	ErrInvalidFiles = errors.New("invalid example files")
+)
+
 type Example struct {
 	ID                int
 	Name              string
@@ -7,5 +24,121 @@ type Example struct {
 	Code              string
 	Output            string
 	HighlightLanguage string
+	AutoFormat        bool
 	Priority          int
+	Files             []File
+	Tags              []string
+	// Published is false for new example until its first revision is published.
+	Published bool
+	// Version is incremented on every saved edit, it is used to detect concurrent edits.
+	Version int
+}
+
+// File is one of named files of multi-file example, e.g. go.mod or main.go.
+type File struct {
+	ID                int
+	Name              string
+	HighlightLanguage string
+	Code              string
+}
+
+var extensions = map[string]string{
+	"go":         ".go",
+	"golang":     ".go",
+	"json":       ".json",
+	"yaml":       ".yaml",
+	"sql":        ".sql",
+	"bash":       ".sh",
+	"shell":      ".sh",
+	"python":     ".py",
+	"javascript": ".js",
+	"typescript": ".ts",
+	"html":       ".html",
+	"css":        ".css",
+	"xml":        ".xml",
+}
+
+// CleanFileName returns file name in canonical form, e.g. "./cmd//main.go" becomes "cmd/main.go".
+// Names must be relative paths inside example, so archive of example can't write files outside
+// the directory it is unpacked to.
+func CleanFileName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: file name can't be empty", ErrInvalidFiles)
+	}
+	if strings.Contains(name, `\`) || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("%w: file name %q must be relative path with slashes", ErrInvalidFiles, name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("%w: file name %q can't refer to parent directory", ErrInvalidFiles, name)
+		}
+	}
+
+	name = path.Clean(name)
+	if name == "." {
+		return "", fmt.Errorf("%w: file name can't be empty", ErrInvalidFiles)
+	}
+
+	return name, nil
+}
+
+// ValidateFiles checks that files of example have valid unique names. Example with files can't have
+// its own code, it would be hidden by files.
+func (e Example) ValidateFiles() error {
+	if len(e.Files) == 0 {
+		return nil
+	}
+
+	if strings.TrimSpace(e.Code) != "" {
+		return fmt.Errorf("%w: move code into one of files or remove files", ErrInvalidFiles)
+	}
+
+	seen := make(map[string]bool, len(e.Files))
+	for _, f := range e.Files {
+		name, err := CleanFileName(f.Name)
+		if err != nil {
+			return err
+		}
+		if name != f.Name {
+			return fmt.Errorf("%w: file name %q is not clean, use %q", ErrInvalidFiles, f.Name, name)
+		}
+
+		if seen[name] {
+			return fmt.Errorf("%w: duplicate file name %q", ErrInvalidFiles, name)
+		}
+		seen[name] = true
+	}
+
+	return nil
+}
+
+// AllFiles returns example files. Single-file example is returned as one file named by its language.
+func (e Example) AllFiles() []File {
+	if len(e.Files) != 0 {
+		return e.Files
+	}
+
+	ext, ok := extensions[strings.ToLower(e.HighlightLanguage)]
+	if !ok {
+		ext = ".txt"
+	}
+
+	return []File{{
+		Name:              "main" + ext,
+		HighlightLanguage: e.HighlightLanguage,
+		Code:              e.Code,
+	}}
+}
+
+// FilterPublished returns published examples. It is used to hide drafts from readers.
+func FilterPublished(exas []Example) []Example {
+	res := make([]Example, 0, len(exas))
+	for _, e := range exas {
+		if e.Published {
+			res = append(res, e)
+		}
+	}
+
+	return res
 }
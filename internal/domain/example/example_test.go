@@ -0,0 +1,56 @@
+package example
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanFileName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{" main.go ", "main.go"},
+		{"./cmd//app/main.go", "cmd/app/main.go"},
+		{"dir/", "dir"},
+		{"a..b.go", "a..b.go"},
+		{"../../x", ""},
+		{"cmd/../../x", ""},
+		{"/etc/x", ""},
+		{`..\x`, ""},
+		{`dir\main.go`, ""},
+		{"./", ""},
+		{" ", ""},
+	}
+
+	for _, tt := range tests {
+		got, err := CleanFileName(tt.name)
+		if tt.want == "" {
+			assert.ErrorIs(t, err, ErrInvalidFiles, tt.name)
+			continue
+		}
+
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestExample_ValidateFiles(t *testing.T) {
+	assert.NoError(t, Example{Code: "x"}.ValidateFiles())
+	assert.NoError(t, Example{Files: []File{{Name: "go.mod"}, {Name: "cmd/main.go"}}}.ValidateFiles())
+
+	assert.ErrorIs(t, Example{Code: "x", Files: []File{{Name: "main.go"}}}.ValidateFiles(), ErrInvalidFiles)
+	assert.ErrorIs(t, Example{Files: []File{{Name: "a.go"}, {Name: "a.go"}}}.ValidateFiles(), ErrInvalidFiles)
+	assert.ErrorIs(t, Example{Files: []File{{Name: "../a.go"}}}.ValidateFiles(), ErrInvalidFiles)
+	assert.ErrorIs(t, Example{Files: []File{{Name: "./a.go"}}}.ValidateFiles(), ErrInvalidFiles)
+}
+
+func TestExample_AllFiles(t *testing.T) {
+	files := []File{{Name: "go.mod", Code: "module x"}, {Name: "main.go", Code: "package main"}}
+	assert.Equal(t, files, Example{Files: files}.AllFiles())
+
+	assert.Equal(t, []File{{Name: "main.go", HighlightLanguage: "Go", Code: "package main"}},
+		Example{HighlightLanguage: "Go", Code: "package main"}.AllFiles())
+	assert.Equal(t, "main.txt", Example{HighlightLanguage: "brainfuck"}.AllFiles()[0].Name)
+}
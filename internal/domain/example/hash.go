@@ -0,0 +1,155 @@
+package example
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// DuplicateError is returned when example with the same normalized code already exists.
+type DuplicateError struct {
+	Existing []Example
+}
+
+func (e *DuplicateError) Error() string {
+	ids := make([]string, 0, len(e.Existing))
+	for _, exa := range e.Existing {
+		ids = append(ids, fmt.Sprint(exa.ID))
+	}
+
+	return "example with the same code already exists: " + strings.Join(ids, ", ")
+}
+
+// commentSyntax describes comments and string literals of language.
+type commentSyntax struct {
+	line       string
+	blockStart string
+	blockEnd   string
+	// lineAtWord means line comment starts only at beginning of word, like # in shell.
+	lineAtWord bool
+	quotes     string
+}
+
+var (
+	cLike   = commentSyntax{line: "//", blockStart: "/*", blockEnd: "*/", quotes: "\"'`"}
+	hashTag = commentSyntax{line: "#", lineAtWord: true, quotes: `"'`}
+	markup  = commentSyntax{blockStart: "<!--", blockEnd: "-->", quotes: `"`}
+)
+
+var commentSyntaxes = map[string]commentSyntax{
+	"go":         cLike,
+	"golang":     cLike,
+	"javascript": cLike,
+	"typescript": cLike,
+	"css":        {blockStart: "/*", blockEnd: "*/", quotes: `"'`},
+	"sql":        {line: "--", blockStart: "/*", blockEnd: "*/", quotes: `"'`},
+	"yaml":       hashTag,
+	"bash":       hashTag,
+	"shell":      hashTag,
+	"python":     hashTag,
+	"html":       markup,
+	"xml":        markup,
+	"json":       {quotes: `"`},
+}
+
+// NormalizeCode removes comments and collapses whitespace outside of string literals to single space,
+// so code differing only in indentation and comments is the same. Comments are known for languages of extensions.
+func NormalizeCode(lang string, code string) string {
+	s, ok := commentSyntaxes[strings.ToLower(lang)]
+	if !ok {
+		s = commentSyntax{quotes: `"'`}
+	}
+
+	var b strings.Builder
+	// space is set after whitespace or comment and written before the next code only.
+	space := false
+	for i := 0; i < len(code); {
+		c := code[i]
+		rest := code[i:]
+
+		switch {
+		case strings.IndexByte(s.quotes, c) >= 0:
+			end := stringEnd(code, i)
+			writeSpace(&b, &space)
+			b.WriteString(code[i:end])
+			i = end
+		case s.blockStart != "" && strings.HasPrefix(rest, s.blockStart):
+			end := strings.Index(rest[len(s.blockStart):], s.blockEnd)
+			if end < 0 {
+				i = len(code)
+			} else {
+				i += len(s.blockStart) + end + len(s.blockEnd)
+			}
+			space = true
+		case s.line != "" && strings.HasPrefix(rest, s.line) && (!s.lineAtWord || i == 0 || isSpace(code[i-1])):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				i = len(code)
+			} else {
+				i += end
+			}
+			space = true
+		case isSpace(c):
+			space = true
+			i++
+		default:
+			writeSpace(&b, &space)
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// CodeHash returns hash of normalized code of all example files, or empty string if example has no code.
+func CodeHash(exa Example) string {
+	h := sha256.New()
+	empty := true
+
+	for _, f := range exa.AllFiles() {
+		code := NormalizeCode(f.HighlightLanguage, f.Code)
+		if code != "" {
+			empty = false
+		}
+
+		h.Write([]byte(code))
+		h.Write([]byte{0})
+	}
+
+	if empty {
+		return ""
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// stringEnd returns index after string literal starting at i. Backslash escapes quote except in raw strings.
+func stringEnd(code string, i int) int {
+	quote := code[i]
+	for j := i + 1; j < len(code); j++ {
+		switch code[j] {
+		case '\\':
+			if quote != '`' {
+				j++
+			}
+		case quote:
+			return j + 1
+		}
+	}
+
+	return len(code)
+}
+
+// writeSpace writes pending space unless code is still empty.
+func writeSpace(b *strings.Builder, space *bool) {
+	if *space && b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	*space = false
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
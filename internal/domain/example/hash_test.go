@@ -0,0 +1,44 @@
+package example
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCode(t *testing.T) {
+	tests := []struct {
+		lang string
+		code string
+		want string
+	}{
+		{"go", "// main prints\nfmt.Println( \"a  // b\" ) /* c */\n", `fmt.Println( "a  // b" )`},
+		{"golang", "s := `x\\` // y", "s := `x\\`"},
+		{"python", "x = 1  # one\nprint('# no')", "x = 1 print('# no')"},
+		{"bash", "echo $# # count", "echo $#"},
+		{"sql", "select 1 -- one\n/* two */ from t", "select 1 from t"},
+		{"html", "<p>\n  <!-- note -->hi</p>", "<p> hi</p>"},
+		{"", "a  b // c", "a b // c"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NormalizeCode(tt.lang, tt.code), tt.code)
+	}
+}
+
+func TestCodeHash(t *testing.T) {
+	a := Example{HighlightLanguage: "go", Code: "x := 1 // one\n"}
+	b := Example{HighlightLanguage: "go", Code: "// set x\nx  :=\t1"}
+	c := Example{HighlightLanguage: "go", Code: "x := 2"}
+
+	assert.Equal(t, CodeHash(a), CodeHash(b))
+	assert.NotEqual(t, CodeHash(a), CodeHash(c))
+	assert.NotEqual(t, CodeHash(Example{Code: "return x"}), CodeHash(Example{Code: "returnx"}))
+	assert.Empty(t, CodeHash(Example{HighlightLanguage: "go", Code: "// nothing"}))
+
+	files := Example{Files: []File{
+		{Name: "go.mod", Code: "module x"},
+		{Name: "main.go", HighlightLanguage: "go", Code: "x := 1"},
+	}}
+	assert.NotEqual(t, CodeHash(a), CodeHash(files))
+}
@@ -0,0 +1,183 @@
+package overlap
+
+import (
+	"documentation-mini-app/internal/domain/crossed"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DuplicateThreshold is minimal score of pair of articles to be reported as near-duplicates.
+const DuplicateThreshold = 0.6
+
+// shingleSize is count of consecutive words compared as one unit of text.
+const shingleSize = 3
+
+// Report is overlap of documentations and near-duplicate articles.
+type Report struct {
+	Pairs      []Pair
+	Duplicates []Duplicate
+}
+
+// DocContent is documentation with IDs of its articles and their examples.
+type DocContent struct {
+	Doc        crossed.Entry
+	ArticleIDs []int
+	ExampleIDs []int
+}
+
+// Pair is overlap of two documentations.
+type Pair struct {
+	A, B           crossed.Entry
+	SharedArticles int
+	SharedExamples int
+	// Similarity is Jaccard index of sets of articles and examples of both documentations, from 0 to 1.
+	Similarity float64
+}
+
+// ArticleContent is article with code of its examples.
+type ArticleContent struct {
+	Article     crossed.Entry
+	Description string
+	Code        []string
+}
+
+// Duplicate is pair of articles with similar content.
+type Duplicate struct {
+	A, B crossed.Entry
+	// Description and Code are similarities of descriptions and example code from 0 to 1.
+	// Code is zero if any of articles has no examples.
+	Description float64
+	Code        float64
+	// Score is average of similarities of descriptions and code, or only of descriptions when code is missing.
+	Score float64
+}
+
+// Pairs returns overlap of every pair of documentations, most similar first.
+func Pairs(docs []DocContent) []Pair {
+	items := make([]map[string]bool, len(docs))
+	for i, d := range docs {
+		items[i] = make(map[string]bool, len(d.ArticleIDs)+len(d.ExampleIDs))
+		for _, id := range d.ArticleIDs {
+			items[i]["a"+strconv.Itoa(id)] = true
+		}
+		for _, id := range d.ExampleIDs {
+			items[i]["e"+strconv.Itoa(id)] = true
+		}
+	}
+
+	res := make([]Pair, 0, len(docs)*(len(docs)-1)/2)
+	for i := range docs {
+		for j := i + 1; j < len(docs); j++ {
+			res = append(res, Pair{
+				A:              docs[i].Doc,
+				B:              docs[j].Doc,
+				SharedArticles: countShared(docs[i].ArticleIDs, docs[j].ArticleIDs),
+				SharedExamples: countShared(docs[i].ExampleIDs, docs[j].ExampleIDs),
+				Similarity:     Jaccard(items[i], items[j]),
+			})
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Similarity != res[j].Similarity {
+			return res[i].Similarity > res[j].Similarity
+		}
+
+		return res[i].SharedArticles+res[i].SharedExamples > res[j].SharedArticles+res[j].SharedExamples
+	})
+
+	return res
+}
+
+// Duplicates returns pairs of articles which score is at least threshold, most similar first.
+func Duplicates(arts []ArticleContent, threshold float64) []Duplicate {
+	descs := make([]map[string]bool, len(arts))
+	codes := make([]map[string]bool, len(arts))
+	for i, a := range arts {
+		descs[i] = Shingles(a.Description)
+		codes[i] = Shingles(strings.Join(a.Code, "\n"))
+	}
+
+	res := make([]Duplicate, 0)
+	for i := range arts {
+		for j := i + 1; j < len(arts); j++ {
+			d := Duplicate{A: arts[i].Article, B: arts[j].Article, Description: Jaccard(descs[i], descs[j])}
+
+			d.Score = d.Description
+			if len(codes[i]) > 0 && len(codes[j]) > 0 {
+				d.Code = Jaccard(codes[i], codes[j])
+				d.Score = (d.Description + d.Code) / 2
+			}
+
+			if d.Score >= threshold {
+				res = append(res, d)
+			}
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+
+	return res
+}
+
+// Jaccard returns size of intersection of sets divided by size of their union.
+// It is zero for two empty sets, so empty texts are not similar.
+func Jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// Shingles returns set of sequences of consecutive lowercase words of text.
+// Text shorter than one shingle is one shingle itself.
+func Shingles(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	res := make(map[string]bool)
+	if len(words) == 0 {
+		return res
+	}
+
+	if len(words) < shingleSize {
+		res[strings.Join(words, " ")] = true
+		return res
+	}
+
+	for i := 0; i+shingleSize <= len(words); i++ {
+		res[strings.Join(words[i:i+shingleSize], " ")] = true
+	}
+
+	return res
+}
+
+func countShared(a, b []int) int {
+	set := make(map[int]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+
+	shared := 0
+	for _, id := range b {
+		if set[id] {
+			shared++
+			delete(set, id)
+		}
+	}
+
+	return shared
+}
@@ -0,0 +1,54 @@
+package overlap
+
+import (
+	"documentation-mini-app/internal/domain/crossed"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPairs(t *testing.T) {
+	goDoc := crossed.Entry{ID: 1, Name: "go"}
+	sqlDoc := crossed.Entry{ID: 2, Name: "sql"}
+	jsDoc := crossed.Entry{ID: 3, Name: "js"}
+
+	pairs := Pairs([]DocContent{
+		{Doc: goDoc, ArticleIDs: []int{1, 2}, ExampleIDs: []int{10}},
+		{Doc: sqlDoc, ArticleIDs: []int{2, 3}, ExampleIDs: []int{10, 11}},
+		{Doc: jsDoc, ArticleIDs: []int{4}},
+	})
+
+	require.Len(t, pairs, 3)
+	assert.Equal(t, goDoc, pairs[0].A)
+	assert.Equal(t, sqlDoc, pairs[0].B)
+	assert.Equal(t, 1, pairs[0].SharedArticles)
+	assert.Equal(t, 1, pairs[0].SharedExamples)
+	assert.InDelta(t, 2.0/5, pairs[0].Similarity, 1e-9)
+	assert.Zero(t, pairs[2].Similarity)
+}
+
+func TestDuplicates(t *testing.T) {
+	maps := crossed.Entry{ID: 1, Name: "Maps"}
+	maps2 := crossed.Entry{ID: 2, Name: "Maps again"}
+	slices := crossed.Entry{ID: 3, Name: "Slices"}
+
+	dups := Duplicates([]ArticleContent{
+		{Article: maps, Description: "Maps store keys and values in hash table.", Code: []string{"m := map[string]int{}"}},
+		{Article: maps2, Description: "Maps store keys and values in a hash table!", Code: []string{"m := map[string]int{}"}},
+		{Article: slices, Description: "Slices are views of arrays.", Code: []string{"s := []int{1, 2}"}},
+	}, DuplicateThreshold)
+
+	require.Len(t, dups, 1)
+	assert.Equal(t, maps, dups[0].A)
+	assert.Equal(t, maps2, dups[0].B)
+	assert.Equal(t, 1.0, dups[0].Code)
+	assert.Greater(t, dups[0].Score, DuplicateThreshold)
+}
+
+func TestShingles(t *testing.T) {
+	assert.Equal(t, map[string]bool{"a b c": true, "b c d": true}, Shingles("A, b; c\nd"))
+	assert.Equal(t, map[string]bool{"go": true}, Shingles("Go!"))
+	assert.Empty(t, Shingles(" .. "))
+	assert.Zero(t, Jaccard(Shingles(""), Shingles("")))
+}
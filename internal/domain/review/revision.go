@@ -0,0 +1,134 @@
+package review
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/example"
+	"errors"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusDraft     Status = "draft"
+	StatusReview    Status = "review"
+	StatusApproved  Status = "approved"
+	StatusPublished Status = "published"
+)
+
+const (
+	EntityArticle = "article"
+	EntityExample = "example"
+)
+
+var (
+	ErrWrongStatus = errors.New("action is not allowed in current revision status")
+	ErrNotAuthor   = errors.New("only author can change revision")
+	ErrSelfApprove = errors.New("revision must be approved by another user")
+)
+
+// Revision is pending change of article or example. Its content becomes visible to readers only after
+// it is submitted for review, approved by another user and published.
+type Revision struct {
+	ID         int
+	EntityType string
+	EntityID   int
+	// Article is new content of article if EntityType is EntityArticle.
+	Article *article.Article
+	// Example is new content of example if EntityType is EntityExample.
+	Example   *example.Example
+	Status    Status
+	Author    string
+	Reviewer  string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Name returns name of changed entity.
+func (r *Revision) Name() string {
+	if r.Article != nil {
+		return r.Article.Name
+	}
+
+	if r.Example != nil {
+		return r.Example.Name
+	}
+
+	return ""
+}
+
+// IsOpen reports whether revision is not published yet.
+func (r *Revision) IsOpen() bool {
+	return r.Status != StatusPublished
+}
+
+// Edit checks that user can change content of revision. Edit of revision under review returns it to draft.
+func (r *Revision) Edit(userName string) error {
+	if r.Author != userName {
+		return ErrNotAuthor
+	}
+
+	if !r.IsOpen() {
+		return ErrWrongStatus
+	}
+
+	r.Status = StatusDraft
+	r.Reviewer = ""
+
+	return nil
+}
+
+func (r *Revision) Submit(userName string) error {
+	if r.Author != userName {
+		return ErrNotAuthor
+	}
+
+	if r.Status != StatusDraft {
+		return ErrWrongStatus
+	}
+
+	r.Status = StatusReview
+
+	return nil
+}
+
+func (r *Revision) Approve(userName string) error {
+	if r.Author == userName {
+		return ErrSelfApprove
+	}
+
+	if r.Status != StatusReview {
+		return ErrWrongStatus
+	}
+
+	r.Status = StatusApproved
+	r.Reviewer = userName
+
+	return nil
+}
+
+// Reject returns revision under review or approved one to its author.
+func (r *Revision) Reject(userName string) error {
+	if r.Author == userName {
+		return ErrSelfApprove
+	}
+
+	if r.Status != StatusReview && r.Status != StatusApproved {
+		return ErrWrongStatus
+	}
+
+	r.Status = StatusDraft
+	r.Reviewer = userName
+
+	return nil
+}
+
+func (r *Revision) Publish() error {
+	if r.Status != StatusApproved {
+		return ErrWrongStatus
+	}
+
+	r.Status = StatusPublished
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package review
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevision_Workflow(t *testing.T) {
+	r := Revision{Status: StatusDraft, Author: "alice"}
+
+	assert.ErrorIs(t, r.Submit("bob"), ErrNotAuthor)
+	assert.ErrorIs(t, r.Publish(), ErrWrongStatus)
+
+	assert.NoError(t, r.Submit("alice"))
+	assert.Equal(t, StatusReview, r.Status)
+
+	assert.ErrorIs(t, r.Approve("alice"), ErrSelfApprove)
+	assert.NoError(t, r.Approve("bob"))
+	assert.Equal(t, StatusApproved, r.Status)
+	assert.Equal(t, "bob", r.Reviewer)
+
+	assert.NoError(t, r.Publish())
+	assert.Equal(t, StatusPublished, r.Status)
+	assert.False(t, r.IsOpen())
+
+	assert.ErrorIs(t, r.Edit("alice"), ErrWrongStatus)
+}
+
+func TestRevision_RejectAndEdit(t *testing.T) {
+	r := Revision{Status: StatusReview, Author: "alice"}
+
+	assert.NoError(t, r.Reject("bob"))
+	assert.Equal(t, StatusDraft, r.Status)
+
+	assert.NoError(t, r.Submit("alice"))
+	assert.NoError(t, r.Edit("alice"))
+	assert.Equal(t, StatusDraft, r.Status)
+}
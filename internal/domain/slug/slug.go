@@ -0,0 +1,76 @@
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Empty is slug of name that has no letters or digits.
+const Empty = "untitled"
+
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z", 'и': "i",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+	'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+	'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// Make returns slug of name: lowercased latin letters and digits separated by single hyphens.
+// Cyrillic letters are transliterated, other characters are treated as separators.
+func Make(name string) string {
+	var b strings.Builder
+	sep := false
+
+	for _, r := range strings.ToLower(name) {
+		s, ok := translit[r]
+		switch {
+		case ok:
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			s = string(r)
+		default:
+			sep = true
+			continue
+		}
+
+		if s == "" {
+			continue
+		}
+		if sep && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		sep = false
+
+		b.WriteString(s)
+	}
+
+	if b.Len() == 0 {
+		return Empty
+	}
+
+	return b.String()
+}
+
+// WithSuffix returns n-th variant of slug base used to make it unique. First variant is base itself.
+func WithSuffix(base string, n int) string {
+	if n <= 1 {
+		return base
+	}
+
+	return base + "-" + strconv.Itoa(n)
+}
+
+// HasBase reports whether s is one of variants of slug base made by WithSuffix.
+func HasBase(s string, base string) bool {
+	if s == base {
+		return true
+	}
+
+	n, ok := strings.CutPrefix(s, base+"-")
+	if !ok {
+		return false
+	}
+
+	i, err := strconv.Atoi(n)
+	return err == nil && i > 1 && strconv.Itoa(i) == n
+}
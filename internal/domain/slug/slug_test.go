@@ -0,0 +1,24 @@
+package slug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	assert.Equal(t, "obrabotka-oshibok-v-go", Make("Обработка ошибок в Go"))
+	assert.Equal(t, "shchuka-i-yozh", Make("  Щука и ёж!  "))
+	assert.Equal(t, "http-2-0", Make("HTTP/2.0"))
+	assert.Equal(t, "podezd", Make("Подъезд"))
+	assert.Equal(t, Empty, Make("!!!"))
+	assert.Equal(t, Empty, Make("日本"))
+}
+
+func TestHasBase(t *testing.T) {
+	assert.True(t, HasBase("go", "go"))
+	assert.True(t, HasBase(WithSuffix("go", 3), "go"))
+	assert.False(t, HasBase("go-1", "go"))
+	assert.False(t, HasBase("go-02", "go"))
+	assert.False(t, HasBase("go-lang", "go"))
+}
@@ -0,0 +1,45 @@
+package tag
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/example"
+	"strings"
+)
+
+// Tag is free-form label of articles and examples.
+type Tag struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Tagged is list of articles and examples, e.g. marked by some tag or found by search.
+type Tagged struct {
+	Tag      string
+	Query    string
+	Articles []article.Article
+	Examples []example.Example
+}
+
+// Normalize returns tag in canonical form: lowercased, without commas and with single spaces.
+func Normalize(s string) string {
+	s = strings.ReplaceAll(s, ",", " ")
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// Parse splits comma separated tags into unique normalized tags.
+func Parse(s string) []string {
+	res := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, t := range strings.Split(s, ",") {
+		t = Normalize(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+
+		res = append(res, t)
+	}
+
+	return res
+}
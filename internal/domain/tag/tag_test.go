@@ -0,0 +1,12 @@
+package tag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	assert.Equal(t, []string{"go", "error handling"}, Parse(" Go,error   Handling,, go "))
+	assert.Equal(t, []string{}, Parse(""))
+}
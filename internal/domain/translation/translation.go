@@ -0,0 +1,79 @@
+package translation
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/i18n"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// Source is locale in which articles are written, translations are made from it.
+const Source = i18n.Default
+
+var (
+	ErrNotFound = errors.New("translation not found")
+	// ErrLocale is returned for locale which can't have translation.
+	ErrLocale    = errors.New("unsupported translation locale")
+	ErrEmptyName = errors.New("translated name can't be empty")
+	// ErrSelfPublish is returned when author of translation tries to publish it.
+	ErrSelfPublish = errors.New("translation must be published by another user")
+)
+
+// Translation is content of article and descriptions of its examples in another locale.
+// It is shown to readers only after another user publishes it, every save makes it unpublished again.
+type Translation struct {
+	ArticleID   int
+	Locale      string
+	Name        string
+	Description string
+	// SourceVersion is version of article the translation was made from.
+	SourceVersion int
+	// Examples are translated descriptions by example id.
+	Examples  map[int]Example
+	Author    string
+	UpdatedAt time.Time
+	Published bool
+}
+
+type Example struct {
+	ExampleID   int
+	Description string
+	// SourceVersion is version of example the translation was made from.
+	SourceVersion int
+}
+
+var localePattern = regexp.MustCompile(`^[a-z]{2}$`)
+
+// ValidLocale reports whether article can be translated to locale.
+func ValidLocale(loc string) bool {
+	return loc != Source && localePattern.MatchString(loc)
+}
+
+// Outdated reports whether article was edited after the translation was made.
+func (t *Translation) Outdated(art *article.Article) bool {
+	return art.Version > t.SourceVersion
+}
+
+// ExampleOutdated reports whether example has translated description and was edited after it was made.
+func (t *Translation) ExampleOutdated(exa example.Example) bool {
+	e, ok := t.Examples[exa.ID]
+	return ok && exa.Version > e.SourceVersion
+}
+
+// Apply replaces content of article and its examples with translated one. Examples without translated
+// description keep the source one.
+func (t *Translation) Apply(art *article.Article) {
+	art.Name = t.Name
+	art.Description = t.Description
+
+	exas := make([]example.Example, len(art.Examples))
+	for i, exa := range art.Examples {
+		if e, ok := t.Examples[exa.ID]; ok && e.Description != "" {
+			exa.Description = e.Description
+		}
+		exas[i] = exa
+	}
+	art.Examples = exas
+}
@@ -0,0 +1,55 @@
+package translation
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/example"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	art := &article.Article{
+		ID:          1,
+		Name:        "Срезы",
+		Description: "Про срезы",
+		Version:     3,
+		Examples: []example.Example{
+			{ID: 10, Description: "Добавление", Version: 2},
+			{ID: 11, Description: "Копирование", Version: 1},
+		},
+	}
+	source := art.Examples
+
+	tr := &Translation{
+		ArticleID:     1,
+		Locale:        "en",
+		Name:          "Slices",
+		Description:   "About slices",
+		SourceVersion: 2,
+		Examples: map[int]Example{
+			10: {ExampleID: 10, Description: "Append", SourceVersion: 2},
+		},
+	}
+
+	assert.True(t, tr.Outdated(art))
+	assert.False(t, tr.ExampleOutdated(art.Examples[0]))
+	assert.False(t, tr.ExampleOutdated(art.Examples[1]))
+
+	tr.Apply(art)
+	assert.Equal(t, "Slices", art.Name)
+	assert.Equal(t, "About slices", art.Description)
+	assert.Equal(t, "Append", art.Examples[0].Description)
+	assert.Equal(t, "Копирование", art.Examples[1].Description)
+	assert.Equal(t, "Добавление", source[0].Description)
+
+	art.Examples[0].Version = 3
+	assert.True(t, tr.ExampleOutdated(art.Examples[0]))
+}
+
+func TestValidLocale(t *testing.T) {
+	assert.True(t, ValidLocale("en"))
+	assert.False(t, ValidLocale(Source))
+	assert.False(t, ValidLocale("EN"))
+	assert.False(t, ValidLocale("../x"))
+}
@@ -0,0 +1,31 @@
+package trash
+
+import (
+	"errors"
+	"time"
+)
+
+// Entity types that can be moved to trash.
+const (
+	EntityDoc     = "documentation"
+	EntityArticle = "article"
+	EntityExample = "example"
+)
+
+var (
+	ErrNotInTrash    = errors.New("item is not in trash")
+	ErrUnknownEntity = errors.New("unknown entity type")
+)
+
+// Item is deleted documentation, article or example that can be restored until it is purged.
+type Item struct {
+	EntityType string
+	ID         int
+	Name       string
+	DeletedAt  time.Time
+}
+
+// PurgeAt returns time after which item is purged automatically with retention period.
+func (i Item) PurgeAt(retention time.Duration) time.Time {
+	return i.DeletedAt.Add(retention)
+}
@@ -0,0 +1,39 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SessionTTL is lifetime of session after login.
+const SessionTTL = 7 * 24 * time.Hour
+
+var (
+	ErrAnonymous          = errors.New("user must be logged in")
+	ErrInvalidCredentials = errors.New("invalid user name or password")
+	ErrInvalidSession     = errors.New("session is invalid or expired")
+)
+
+type User struct {
+	Name string
+}
+
+type ctxKey struct{}
+
+// NewContext returns ctx with user who performs the request.
+func NewContext(ctx context.Context, u *User) context.Context {
+	return context.WithValue(ctx, ctxKey{}, u)
+}
+
+// FromContext returns user who performs the request. Anonymous readers have no user.
+func FromContext(ctx context.Context) (*User, bool) {
+	u, ok := ctx.Value(ctxKey{}).(*User)
+	return u, ok && u != nil
+}
+
+// IsEditor reports whether request is performed by logged-in user, who can see and change drafts.
+func IsEditor(ctx context.Context) bool {
+	_, ok := FromContext(ctx)
+	return ok
+}
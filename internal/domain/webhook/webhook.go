@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"documentation-mini-app/internal/domain/event"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"time"
+)
+
+// Statuses of delivery.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Headers of webhook request.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// MaxAttempts is number of attempts after which delivery is failed.
+const MaxAttempts = 8
+
+var (
+	ErrNotFound     = errors.New("webhook not found")
+	ErrInvalidURL   = errors.New("webhook url must be absolute http or https url")
+	ErrUnknownEvent = errors.New("unknown webhook event")
+	ErrNoEvents     = errors.New("webhook must have at least one event")
+	ErrNotRetryable = errors.New("only failed delivery can be retried")
+)
+
+// Webhook sends events of documentation to URL. Article and example events are sent to webhooks
+// of every documentation that contains them. Requests are signed by secret.
+type Webhook struct {
+	ID        int
+	DocID     int
+	URL       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+// Subscribed reports whether webhook receives event.
+func (w *Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks URL and events of webhook.
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+
+	if len(w.Events) == 0 {
+		return ErrNoEvents
+	}
+
+	for _, e := range w.Events {
+		if !event.Known(e) {
+			return ErrUnknownEvent
+		}
+	}
+
+	return nil
+}
+
+// Payload is JSON body of webhook request.
+type Payload struct {
+	Event           string    `json:"event"`
+	DocumentationID int       `json:"documentation_id"`
+	EntityType      string    `json:"entity_type"`
+	EntityID        int       `json:"entity_id"`
+	OccurredAt      time.Time `json:"occurred_at"`
+}
+
+// NewPayload returns payload of change for webhook of documentation.
+func NewPayload(c event.Change, docID int) Payload {
+	return Payload{
+		Event:           c.Type,
+		DocumentationID: docID,
+		EntityType:      c.EntityType,
+		EntityID:        c.EntityID,
+		OccurredAt:      c.OccurredAt,
+	}
+}
+
+// Delivery is event queued for sending to webhook. It is kept after sending as delivery log.
+type Delivery struct {
+	ID            int64
+	WebhookID     int
+	Event         string
+	Payload       []byte
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	ResponseCode  int
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+}
+
+// Fail records failed attempt. Delivery is retried after backoff until MaxAttempts is reached.
+func (d *Delivery) Fail(now time.Time, code int, err error) {
+	d.Attempts++
+	d.ResponseCode = code
+	d.LastError = err.Error()
+
+	if d.Attempts >= MaxAttempts {
+		d.Status = StatusFailed
+		return
+	}
+	d.Status = StatusPending
+	d.NextAttemptAt = now.Add(Backoff(d.Attempts))
+}
+
+// Succeed records successful attempt.
+func (d *Delivery) Succeed(now time.Time, code int) {
+	d.Attempts++
+	d.ResponseCode = code
+	d.LastError = ""
+	d.Status = StatusDelivered
+	d.DeliveredAt = &now
+}
+
+// Backoff returns delay before next attempt after attempts failed ones: 30s doubled every attempt
+// up to one hour.
+func Backoff(attempts int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < attempts && d < time.Hour; i++ {
+		d *= 2
+	}
+
+	if d > time.Hour {
+		return time.Hour
+	}
+
+	return d
+}
+
+// Sign returns signature of body by secret in form sha256=<hex of HMAC-SHA256>.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is valid signature of body by secret. Receivers can use it
+// to check requests.
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// NewSecret returns random secret for signing.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"documentation-mini-app/internal/domain/event"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 4*time.Minute, Backoff(4))
+	assert.Equal(t, time.Hour, Backoff(MaxAttempts))
+}
+
+func TestDeliveryFail(t *testing.T) {
+	now := time.Now()
+	d := Delivery{Status: StatusPending}
+
+	d.Fail(now, 500, errors.New("server error"))
+	assert.Equal(t, StatusPending, d.Status)
+	assert.Equal(t, now.Add(30*time.Second), d.NextAttemptAt)
+
+	d.Attempts = MaxAttempts - 1
+	d.Fail(now, 0, errors.New("timeout"))
+	assert.Equal(t, StatusFailed, d.Status)
+	assert.Equal(t, "timeout", d.LastError)
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"article.updated"}`)
+	sig := Sign("secret", body)
+
+	assert.True(t, Verify("secret", body, sig))
+	assert.False(t, Verify("other", body, sig))
+	assert.False(t, Verify("secret", []byte(`{}`), sig))
+}
+
+func TestValidate(t *testing.T) {
+	w := Webhook{URL: "https://bot.example.com/hook", Events: []string{event.ArticleUpdated}}
+	assert.NoError(t, w.Validate())
+
+	w.URL = "ftp://bot.example.com"
+	assert.ErrorIs(t, w.Validate(), ErrInvalidURL)
+
+	w.URL = "http://localhost:9000"
+	w.Events = []string{"article.moved"}
+	assert.ErrorIs(t, w.Validate(), ErrUnknownEvent)
+}
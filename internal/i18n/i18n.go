@@ -0,0 +1,224 @@
+// Package i18n translates UI messages. Catalogs are JSON files named by locale, e.g. en.json, with
+// message keys mapped to text or, for messages depending on count, to plural forms.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	Russian = "ru"
+	English = "en"
+	// Default is locale for clients which prefer none of supported locales, its catalog must be complete.
+	Default = Russian
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// Message is translation of one key. Text is format for fmt.Sprintf, Forms are formats by plural
+// category ("one", "few", "many", "other") for messages depending on count.
+type Message struct {
+	Text  string
+	Forms map[string]string
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		return json.Unmarshal(data, &m.Forms)
+	}
+
+	return json.Unmarshal(data, &m.Text)
+}
+
+// Catalog is translations of one locale by message key.
+type Catalog map[string]Message
+
+// Bundle holds catalogs of all supported locales.
+type Bundle struct {
+	catalogs map[string]Catalog
+}
+
+// New returns bundle of embedded catalogs.
+func New() (*Bundle, error) {
+	sub, err := fs.Sub(locales, "locales")
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(sub)
+}
+
+// Load reads catalogs from JSON files in root of fsys.
+func Load(fsys fs.FS) (*Bundle, error) {
+	files, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{catalogs: make(map[string]Catalog, len(files))}
+	for _, f := range files {
+		data, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+
+		var c Catalog
+		err = json.Unmarshal(data, &c)
+		if err != nil {
+			return nil, fmt.Errorf("catalog %s: %w", f, err)
+		}
+
+		b.catalogs[strings.TrimSuffix(f, path.Ext(f))] = c
+	}
+
+	if _, ok := b.catalogs[Default]; !ok {
+		return nil, fmt.Errorf("catalog of default locale %s not found", Default)
+	}
+
+	return b, nil
+}
+
+// Locales returns supported locales in alphabetical order.
+func (b *Bundle) Locales() []string {
+	res := make([]string, 0, len(b.catalogs))
+	for loc := range b.catalogs {
+		res = append(res, loc)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// Supported reports whether bundle has catalog of locale.
+func (b *Bundle) Supported(loc string) bool {
+	_, ok := b.catalogs[loc]
+	return ok
+}
+
+// T returns message of key in locale formatted with args. Messages missing in locale are taken from
+// default locale, unknown key is returned as is, so it is visible on page.
+func (b *Bundle) T(loc string, key string, args ...interface{}) string {
+	m := b.message(loc, key)
+	if m.Text == "" {
+		return key
+	}
+	if len(args) == 0 {
+		return m.Text
+	}
+
+	return fmt.Sprintf(m.Text, args...)
+}
+
+// N returns plural form of message of key for count n formatted with n and args,
+// e.g. N("ru", "trash.retention", 5) is "5 дней".
+func (b *Bundle) N(loc string, key string, n int, args ...interface{}) string {
+	if !b.Supported(loc) {
+		loc = Default
+	}
+
+	m := b.message(loc, key)
+	form, ok := m.Forms[PluralCategory(loc, n)]
+	if !ok {
+		form, ok = m.Forms["other"]
+	}
+	if !ok {
+		return key
+	}
+
+	return fmt.Sprintf(form, append([]interface{}{n}, args...)...)
+}
+
+func (b *Bundle) message(loc string, key string) Message {
+	if m, ok := b.catalogs[loc][key]; ok {
+		return m
+	}
+
+	return b.catalogs[Default][key]
+}
+
+// PluralCategory returns CLDR plural category of integer n in locale.
+func PluralCategory(loc string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+
+	switch loc {
+	case Russian:
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return "one"
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case English:
+		if n == 1 {
+			return "one"
+		}
+	}
+
+	return "other"
+}
+
+// Match returns supported locale most preferred by Accept-Language header, e.g. "en-US,en;q=0.9,ru;q=0.8".
+// Regions are ignored. Default is returned if no language is supported.
+func (b *Bundle) Match(acceptLanguage string) string {
+	type pref struct {
+		loc string
+		q   float64
+	}
+
+	var prefs []pref
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		lang, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if q > 0 && b.Supported(lang) {
+			prefs = append(prefs, pref{loc: lang, q: q})
+		}
+	}
+
+	sort.SliceStable(prefs, func(i, j int) bool {
+		return prefs[i].q > prefs[j].q
+	})
+	if len(prefs) == 0 {
+		return Default
+	}
+
+	return prefs[0].loc
+}
+
+type ctxKey struct{}
+
+// NewContext returns ctx with locale of the request.
+func NewContext(ctx context.Context, loc string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, loc)
+}
+
+// FromContext returns locale of the request or Default.
+func FromContext(ctx context.Context) string {
+	loc, ok := ctx.Value(ctxKey{}).(string)
+	if !ok {
+		return Default
+	}
+
+	return loc
+}
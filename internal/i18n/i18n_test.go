@@ -0,0 +1,74 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		loc  string
+		n    int
+		want string
+	}{
+		{Russian, 1, "one"},
+		{Russian, 21, "one"},
+		{Russian, 2, "few"},
+		{Russian, 24, "few"},
+		{Russian, 5, "many"},
+		{Russian, 11, "many"},
+		{Russian, 12, "many"},
+		{Russian, 0, "many"},
+		{English, 1, "one"},
+		{English, 0, "other"},
+		{English, 21, "other"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, PluralCategory(tt.loc, tt.n), "%s %d", tt.loc, tt.n)
+	}
+}
+
+func TestCatalogs(t *testing.T) {
+	b, err := New()
+	require.NoError(t, err)
+	assert.Equal(t, []string{English, Russian}, b.Locales())
+
+	for key, m := range b.catalogs[Default] {
+		en, ok := b.catalogs[English][key]
+		if assert.True(t, ok, key) {
+			assert.Equal(t, m.Forms == nil, en.Forms == nil, key)
+		}
+	}
+}
+
+func TestBundle(t *testing.T) {
+	b, err := Load(fstest.MapFS{
+		"ru.json": {
+			Data: []byte(`{"hello": "Привет, %s", "days": {"one": "%d день", "few": "%d дня", "many": "%d дней"}}`),
+		},
+		"en.json": {Data: []byte(`{"days": {"one": "%d day", "other": "%d days"}}`)},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Привет, Go", b.T(English, "hello", "Go"))
+	assert.Equal(t, "missing", b.T(English, "missing"))
+	assert.Equal(t, "1 day", b.N(English, "days", 1))
+	assert.Equal(t, "3 days", b.N(English, "days", 3))
+	assert.Equal(t, "3 дня", b.N(Russian, "days", 3))
+	assert.Equal(t, "25 дней", b.N("de", "days", 25))
+}
+
+func TestMatch(t *testing.T) {
+	b, err := New()
+	require.NoError(t, err)
+
+	assert.Equal(t, English, b.Match("en-US,en;q=0.9,ru;q=0.8"))
+	assert.Equal(t, Russian, b.Match("de-DE,ru;q=0.5,en;q=0.4"))
+	assert.Equal(t, English, b.Match("ru;q=0,en"))
+	assert.Equal(t, Default, b.Match("de"))
+	assert.Equal(t, Default, b.Match(""))
+}
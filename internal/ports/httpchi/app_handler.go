@@ -5,10 +5,17 @@ import (
 	"documentation-mini-app/internal/domain/article"
 	"documentation-mini-app/internal/domain/crossed"
 	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/user"
+	"documentation-mini-app/internal/views/exportview"
 	"documentation-mini-app/internal/views/htmlview"
+	"fmt"
 	"github.com/go-chi/chi/v5"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 type AppUsecase interface {
@@ -16,31 +23,67 @@ type AppUsecase interface {
 	GetAllDoc(ctx context.Context) ([]*doc.Documentation, error)
 	GetCrossed(ctx context.Context) (*crossed.Crossed, error)
 	GetArticlesWithoutDoc(ctx context.Context) ([]article.Article, error)
+	FilterByTag(ctx context.Context, docs []*doc.Documentation, t string) error
+}
+
+// contentsPage is the data of contents template.
+type contentsPage struct {
+	Docs []*doc.Documentation
+	User string
+	Tag  string
+}
+
+// crossedPage is the data of crossed template.
+type crossedPage struct {
+	*crossed.Crossed
+	Filter crossed.Filter
+	// AllDocs are documentations to choose from in filter.
+	AllDocs []crossed.Entry
+	// Query is filter encoded to keep it in export links.
+	Query url.Values
+}
+
+// ExportURL returns link to export of shown part of matrix in format.
+func (p crossedPage) ExportURL(format string) string {
+	q := url.Values{"format": {format}}
+	for k, v := range p.Query {
+		q[k] = v
+	}
+
+	return "/crossed?" + q.Encode()
+}
+
+// Selected reports whether documentation is chosen in filter.
+func (p crossedPage) Selected(docID int) bool {
+	for _, id := range p.Filter.DocIDs {
+		if id == docID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RoutesSetter is handler of some entity that registers its own routes.
+type RoutesSetter interface {
+	SetupRoutes(r chi.Router)
 }
 
 type AppHandler struct {
 	router chi.Router
 	uc     AppUsecase
 
-	contentView *htmlview.TemplateView
-	crossedView *htmlview.TemplateView
+	views *htmlview.Registry
 
-	artHandler     *ArticleHandler
-	docHandler     *DocHandler
-	exampleHandler *ExampleHandler
+	handlers []RoutesSetter
 }
 
-func NewAppHandler(r chi.Router, uc AppUsecase, ah *ArticleHandler, dh *DocHandler, eh *ExampleHandler,
-	contentsView *htmlview.TemplateView, crossedView *htmlview.TemplateView,
-) *AppHandler {
+func NewAppHandler(r chi.Router, uc AppUsecase, views *htmlview.Registry, handlers ...RoutesSetter) *AppHandler {
 	h := &AppHandler{
-		router:         r,
-		uc:             uc,
-		contentView:    contentsView,
-		crossedView:    crossedView,
-		artHandler:     ah,
-		docHandler:     dh,
-		exampleHandler: eh,
+		router:   r,
+		uc:       uc,
+		views:    views,
+		handlers: handlers,
 	}
 	h.SetupRoutes()
 
@@ -59,9 +102,9 @@ func (h *AppHandler) SetupRoutes() {
 }
 
 func (h *AppHandler) setupOtherRoutes(r chi.Router) {
-	h.artHandler.SetupRoutes(r)
-	h.docHandler.SetupRoutes(r)
-	h.exampleHandler.SetupRoutes(r)
+	for _, handler := range h.handlers {
+		handler.SetupRoutes(r)
+	}
 }
 
 func (h *AppHandler) GetContents() http.HandlerFunc {
@@ -80,32 +123,120 @@ func (h *AppHandler) GetContents() http.HandlerFunc {
 			return
 		}
 
-		docs = append(docs, &doc.Documentation{
-			Name:     "Статьи без документации",
-			Articles: artsWithoutDoc,
-		})
+		// Documentation without ID holds articles without documentation, template names it.
+		docs = append(docs, &doc.Documentation{Articles: artsWithoutDoc})
+
+		t := r.URL.Query().Get("tag")
+		if t != "" {
+			err = h.uc.FilterByTag(r.Context(), docs, t)
+			if err != nil {
+				log.Println(err)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		page := contentsPage{Docs: docs, Tag: t}
+		if u, ok := user.FromContext(r.Context()); ok {
+			page.User = u.Name
+		}
 
 		w.WriteHeader(http.StatusOK)
 
-		err = h.contentView.ToWriter(w, docs)
+		err = h.views.Render(r.Context(), w, "contents", page)
 		if err != nil {
 			log.Println(err)
 		}
 	}
 }
 
+// crossedFormats are media types of crossed matrix exports by value of format parameter.
+var crossedFormats = []struct {
+	format      string
+	contentType string
+	write       func(io.Writer, crossed.Crossed, exportview.Labels) error
+}{
+	{"csv", "text/csv; charset=utf-8", exportview.CrossedToCSV},
+	{"xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", exportview.CrossedToXLSX},
+	{"json", "application/json", exportview.CrossedToJSON},
+}
+
+// GetCrossed renders matrix of articles in documentations. Matrix is exported as CSV, XLSX or JSON
+// if format is set or its media type is accepted.
 func (h *AppHandler) GetCrossed() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		crsd, err := h.uc.GetCrossed(r.Context())
+		query := r.URL.Query()
+		format := query.Get("format")
+		accept := r.Header.Get("Accept")
+		w.Header().Set("Vary", "Accept")
+
+		f, err := parseCrossedFilter(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		all, err := h.uc.GetCrossed(r.Context())
 		if err != nil {
 			log.Println(err)
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		crsd := all.Apply(f)
+
+		for _, exp := range crossedFormats {
+			mediaType, _, _ := strings.Cut(exp.contentType, ";")
+			if format != exp.format && (format != "" || !strings.Contains(accept, mediaType)) {
+				continue
+			}
+
+			w.Header().Set("Content-Type", exp.contentType)
+			if exp.format != "json" {
+				w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="crossed.%s"`, exp.format))
+			}
+
+			err = exp.write(w, crsd, exportview.Labels{
+				Documentation: h.views.T(r.Context(), "common.documentation"),
+				ArticleID:     h.views.T(r.Context(), "crossed.article_id"),
+				Total:         h.views.T(r.Context(), "common.total"),
+				Sheet:         h.views.T(r.Context(), "crossed.sheet"),
+			})
+			if err != nil {
+				log.Println(err)
+			}
+			return
+		}
+
+		if format != "" && format != "html" {
+			http.Error(w, fmt.Sprintf("unknown format %q", format), http.StatusBadRequest)
+			return
+		}
 
-		err = h.crossedView.ToWriter(w, crsd)
+		query.Del("format")
+		page := crossedPage{Crossed: &crsd, Filter: f, AllDocs: all.Docs, Query: query}
+
+		err = h.views.Render(r.Context(), w, "crossed", page)
 		if err != nil {
 			log.Println(err)
 		}
 	}
 }
+
+// parseCrossedFilter makes filter from query with repeated doc IDs, shared flag and sort order.
+func parseCrossedFilter(q url.Values) (crossed.Filter, error) {
+	f := crossed.Filter{Shared: q.Get("shared") != "", Sort: q.Get("sort")}
+
+	for _, v := range q["doc"] {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return f, fmt.Errorf("invalid documentation id %q", v)
+		}
+		f.DocIDs = append(f.DocIDs, id)
+	}
+
+	if f.Sort != "" && f.Sort != crossed.SortName && f.Sort != crossed.SortTotal {
+		return f, fmt.Errorf("unknown sort %q", f.Sort)
+	}
+
+	return f, nil
+}
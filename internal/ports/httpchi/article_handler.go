@@ -3,7 +3,15 @@ package httpchi
 import (
 	"context"
 	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/comment"
+	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/review"
+	"documentation-mini-app/internal/domain/tag"
+	"documentation-mini-app/internal/domain/translation"
+	"documentation-mini-app/internal/domain/user"
+	"documentation-mini-app/internal/i18n"
 	"documentation-mini-app/internal/views/htmlview"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"io"
@@ -16,47 +24,84 @@ import (
 
 type ArticleUsecase interface {
 	GetArticleByID(ctx context.Context, id int) (*article.Article, error)
-	CreateArticle(ctx context.Context, art *article.Article) error
-	AddArticleToDoc(ctx context.Context, artID int, docID int) error
-	UpdateArticle(ctx context.Context, art *article.Article) error
+	GetArticleBySlug(ctx context.Context, docSlug string, artSlug string) (*article.Article, string, error)
+	GetArticleDocSlug(ctx context.Context, artID int) (string, error)
+	GetArticleTranslation(ctx context.Context, artID int, locale string) (*translation.Translation, []string, error)
+	GetTextLinks(ctx context.Context, text string) ([]article.Link, error)
+	GetArticleForEdit(ctx context.Context, id int) (*article.Article, error)
+	GetArticleRevisions(ctx context.Context, id int) ([]review.Revision, error)
+	GetArticleThreads(ctx context.Context, id int) ([]comment.Thread, error)
+	CreateArticle(ctx context.Context, art *article.Article, docID int) error
+	SaveArticleDraft(ctx context.Context, art *article.Article) (*review.Revision, error)
 	DeleteArticle(ctx context.Context, artID int) error
 }
 
+// articlePage is the data of article template.
+type articlePage struct {
+	*article.Article
+	Revisions []review.Revision
+	Threads   []comment.Thread
+	Editor    bool
+	// Locale is locale of shown content, Locales are all locales article can be read in.
+	Locale  string
+	Locales []string
+	// Translation is nil if article is shown in source locale.
+	Translation *translation.Translation
+}
+
+// NotTranslated reports whether article is shown in source locale because it is not translated to locale of UI.
+func (p articlePage) NotTranslated(uiLocale string) bool {
+	return p.Translation == nil && uiLocale != p.Locale
+}
+
+// ExampleThreads returns threads attached to example of article, or to article itself if exaID is zero.
+func (p articlePage) ExampleThreads(exaID int) []comment.Thread {
+	res := make([]comment.Thread, 0)
+	for _, t := range p.Threads {
+		if t.ExampleID == exaID {
+			res = append(res, t)
+		}
+	}
+
+	return res
+}
+
 type ArticleHandler struct {
 	uc ArticleUsecase
 
-	getAV    *htmlview.TemplateView
-	createAV *htmlview.TemplateView
-	editAV   *htmlview.TemplateView
-	deleteAV *htmlview.TemplateView
+	views *htmlview.Registry
 }
 
-func NewArticleHandler(uc ArticleUsecase,
-	getArticleView *htmlview.TemplateView, createArticleView *htmlview.TemplateView,
-	editArticleView *htmlview.TemplateView, deleteArticleView *htmlview.TemplateView,
-) *ArticleHandler {
-	return &ArticleHandler{uc: uc,
-		getAV: getArticleView, createAV: createArticleView,
-		editAV: editArticleView, deleteAV: deleteArticleView}
+func NewArticleHandler(uc ArticleUsecase, views *htmlview.Registry) *ArticleHandler {
+	return &ArticleHandler{uc: uc, views: views}
 }
 
 func (h *ArticleHandler) SetupRoutes(r chi.Router) {
+	r.Get("/docs/{docSlug}/{articleSlug}", h.GetArticleBySlug())
+
 	r.Route("/articles/{articleID}", func(r chi.Router) {
 		r.Get("/", h.GetArticle())
 
-		r.Get("/edit", h.GetEditArticle())
-		r.Post("/edit", h.EditArticle())
+		r.Group(func(r chi.Router) {
+			r.Use(RequireUser)
+
+			r.Get("/edit", h.GetEditArticle())
+			r.Post("/edit", h.EditArticle())
 
-		r.Get("/delete", h.GetDeleteArticle())
-		r.Post("/delete", h.DeleteArticle())
+			r.Get("/delete", h.GetDeleteArticle())
+			r.Post("/delete", h.DeleteArticle())
+		})
 	})
 
 	r.Route("/documentations/{docID}/articles/create", func(r chi.Router) {
+		r.Use(RequireUser)
+
 		r.Get("/", h.GetCreateArticle())
 		r.Post("/", h.CreateArticle())
 	})
 }
 
+// GetArticle redirects to article URL with slugs. Article that belongs to no documentation is rendered in place.
 func (h *ArticleHandler) GetArticle() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		artID, err := strconv.Atoi(chi.URLParam(r, "articleID"))
@@ -67,23 +112,118 @@ func (h *ArticleHandler) GetArticle() http.HandlerFunc {
 		}
 
 		art, err := h.uc.GetArticleByID(r.Context(), artID)
+		if errors.Is(err, article.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			log.Println(err)
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		docSlug, err := h.uc.GetArticleDocSlug(r.Context(), artID)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if docSlug != "" && art.Slug != "" {
+			redirectPermanent(w, r, articlePath(docSlug, art.Slug))
 			return
 		}
 
-		err = h.getAV.ToWriter(w, art)
+		h.writeArticle(w, r, art)
+	}
+}
+
+// GetArticleBySlug renders article by slugs of documentation and article.
+// Article found by previous slugs is redirected to its current URL.
+func (h *ArticleHandler) GetArticleBySlug() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docSlug := chi.URLParam(r, "docSlug")
+		artSlug := chi.URLParam(r, "articleSlug")
+
+		art, curDocSlug, err := h.uc.GetArticleBySlug(r.Context(), docSlug, artSlug)
+		if errors.Is(err, article.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if curDocSlug == "" {
+			redirectPermanent(w, r, fmt.Sprintf("/articles/%v", art.ID))
+			return
+		}
+
+		if curDocSlug != docSlug || art.Slug != artSlug {
+			redirectPermanent(w, r, articlePath(curDocSlug, art.Slug))
+			return
 		}
+
+		h.writeArticle(w, r, art)
+	}
+}
+
+// writeArticle writes article page with revisions and discussions visible to current user.
+func (h *ArticleHandler) writeArticle(w http.ResponseWriter, r *http.Request, art *article.Article) {
+	revs, err := h.uc.GetArticleRevisions(r.Context(), art.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	threads, err := h.uc.GetArticleThreads(r.Context(), art.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	tr, locales, err := h.uc.GetArticleTranslation(r.Context(), art.ID, i18n.FromContext(r.Context()))
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	locale := translation.Source
+	if tr != nil {
+		tr.Apply(art)
+		locale = tr.Locale
+
+		art.Links, err = h.uc.GetTextLinks(r.Context(), art.Description)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err = h.views.Render(r.Context(), w, "articles/get_article", articlePage{
+		Article:     art,
+		Revisions:   revs,
+		Threads:     threads,
+		Editor:      user.IsEditor(r.Context()),
+		Locale:      locale,
+		Locales:     locales,
+		Translation: tr,
+	})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
 func (h *ArticleHandler) GetCreateArticle() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		err := h.createAV.ToWriter(w, struct{}{})
+		err := h.views.Render(r.Context(), w, "articles/create_article", struct{}{})
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -116,6 +256,7 @@ func (h *ArticleHandler) CreateArticle() http.HandlerFunc {
 
 		name := q.Get("name")
 		desc := q.Get("description")
+		tags := tag.Parse(q.Get("tags"))
 
 		if name == "" {
 			http.Error(w, "name can't be empty", http.StatusBadRequest)
@@ -125,16 +266,16 @@ func (h *ArticleHandler) CreateArticle() http.HandlerFunc {
 		art := article.Article{
 			Name:        name,
 			Description: desc,
+			Tags:        tags,
 		}
 
-		err = h.uc.CreateArticle(r.Context(), &art)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		err = h.uc.CreateArticle(r.Context(), &art, docID)
+		if errors.Is(err, doc.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
-
-		err = h.uc.AddArticleToDoc(r.Context(), art.ID, docID)
 		if err != nil {
+			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -152,14 +293,15 @@ func (h *ArticleHandler) GetEditArticle() http.HandlerFunc {
 			return
 		}
 
-		art, err := h.uc.GetArticleByID(r.Context(), artID)
+		art, err := h.uc.GetArticleForEdit(r.Context(), artID)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		err = h.editAV.ToWriter(w, art)
+		w.Header().Set("ETag", etag(art.Version))
+		err = h.views.Render(r.Context(), w, "articles/edit_article", art)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -192,25 +334,69 @@ func (h *ArticleHandler) EditArticle() http.HandlerFunc {
 
 		name := q.Get("name")
 		desc := q.Get("description")
+		tags := tag.Parse(q.Get("tags"))
 
 		if name == "" {
 			http.Error(w, "name can't be empty", http.StatusBadRequest)
 			return
 		}
 
+		version, fromHeader, err := requestVersion(r, q)
+		if err != nil {
+			writeVersionError(w, err)
+			return
+		}
+
 		art := article.Article{
 			ID:          artID,
 			Name:        name,
 			Description: desc,
+			Tags:        tags,
+			Version:     version,
 		}
 
-		err = h.uc.UpdateArticle(r.Context(), &art)
+		rev, err := h.uc.SaveArticleDraft(r.Context(), &art)
+		if errors.Is(err, article.ErrConflict) {
+			h.writeConflict(w, r, fromHeader, &art)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		http.Redirect(w, r, fmt.Sprintf("/articles/%v", art.ID), http.StatusSeeOther)
+		http.Redirect(w, r, fmt.Sprintf("/revisions/%v", rev.ID), http.StatusSeeOther)
+	}
+}
+
+// writeConflict renders page to merge article edited by user with article saved by someone else.
+func (h *ArticleHandler) writeConflict(w http.ResponseWriter, r *http.Request, fromHeader bool,
+	mine *article.Article,
+) {
+	theirs, err := h.uc.GetArticleByID(r.Context(), mine.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeConflict(r.Context(), w, h.views, fromHeader, articleConflict(mine, theirs))
+}
+
+// articleConflict returns conflict page of article edited by user and article saved by someone else.
+func articleConflict(mine, theirs *article.Article) conflictPage {
+	return conflictPage{
+		Title:   theirs.Name,
+		Action:  fmt.Sprintf("/articles/%v/edit", theirs.ID),
+		Back:    fmt.Sprintf("/articles/%v", theirs.ID),
+		Version: theirs.Version,
+		Fields: []conflictField{
+			{Name: "name", Label: "form.name", Mine: mine.Name, Theirs: theirs.Name},
+			{Name: "description", Label: "form.description", Mine: mine.Description, Theirs: theirs.Description,
+				Long: true},
+			{Name: "tags", Label: "form.tags", Mine: strings.Join(mine.Tags, ", "),
+				Theirs: strings.Join(theirs.Tags, ", ")},
+		},
 	}
 }
 
@@ -224,13 +410,17 @@ func (h *ArticleHandler) GetDeleteArticle() http.HandlerFunc {
 		}
 
 		art, err := h.uc.GetArticleByID(r.Context(), artID)
+		if errors.Is(err, article.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			log.Println(err)
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		err = h.deleteAV.ToWriter(w, art)
+		err = h.views.Render(r.Context(), w, "articles/delete_article", art)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -256,3 +446,8 @@ func (h *ArticleHandler) DeleteArticle() http.HandlerFunc {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 	}
 }
+
+// articlePath returns URL path of article with slug in documentation with slug.
+func articlePath(docSlug string, artSlug string) string {
+	return docPath(docSlug) + "/" + url.PathEscape(artSlug)
+}
@@ -0,0 +1,164 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/views/htmlview"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// auditPageLimit is max number of entries shown on audit page. Export has no limit.
+const auditPageLimit = 200
+
+const auditDateLayout = "2006-01-02"
+
+type AuditUsecase interface {
+	GetEntries(ctx context.Context, f audit.Filter) ([]audit.Entry, error)
+}
+
+// auditPage is the data of audit template. Query holds filter values to refill the form.
+type auditPage struct {
+	Entries     []audit.Entry
+	Query       url.Values
+	Limit       int
+	Actions     []string
+	EntityTypes []string
+}
+
+// ExportURL returns URL of JSON lines export with the same filter.
+func (p auditPage) ExportURL() string {
+	q := url.Values{}
+	for k, v := range p.Query {
+		q[k] = v
+	}
+	q.Set("format", "jsonl")
+
+	return "/admin/audit?" + q.Encode()
+}
+
+type AuditHandler struct {
+	uc AuditUsecase
+
+	views *htmlview.Registry
+}
+
+func NewAuditHandler(uc AuditUsecase, views *htmlview.Registry) *AuditHandler {
+	return &AuditHandler{uc: uc, views: views}
+}
+
+func (h *AuditHandler) SetupRoutes(r chi.Router) {
+	r.Route("/admin/audit", func(r chi.Router) {
+		r.Use(RequireUser)
+
+		r.Get("/", h.GetAudit())
+	})
+}
+
+// AuditMiddleware puts request ID and client IP to context for audit entries. It must be used after
+// middleware.RequestID.
+func AuditMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+
+		ctx := audit.NewContext(r.Context(), audit.Request{ID: middleware.GetReqID(r.Context()), IP: ip})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetAudit renders audit entries matched by filter from query. Entries are written as JSON lines
+// if format=jsonl is set.
+func (h *AuditHandler) GetAudit() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		export := query.Get("format") == "jsonl"
+		query.Del("format")
+
+		f, err := parseAuditFilter(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !export {
+			f.Limit = auditPageLimit
+		}
+
+		entries, err := h.uc.GetEntries(r.Context(), f)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if export {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Content-Disposition", `attachment; filename="audit.jsonl"`)
+
+			enc := json.NewEncoder(w)
+			for _, e := range entries {
+				err = enc.Encode(e)
+				if err != nil {
+					log.Println(err)
+					return
+				}
+			}
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "admin/audit", auditPage{
+			Entries:     entries,
+			Query:       query,
+			Limit:       auditPageLimit,
+			Actions:     audit.Actions,
+			EntityTypes: audit.EntityTypes,
+		})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// parseAuditFilter makes filter from query. Dates are inclusive days in format 2006-01-02.
+func parseAuditFilter(q url.Values) (audit.Filter, error) {
+	f := audit.Filter{
+		Actor:      q.Get("actor"),
+		Action:     q.Get("action"),
+		EntityType: q.Get("entity_type"),
+	}
+
+	var err error
+	if s := q.Get("entity_id"); s != "" {
+		f.EntityID, err = strconv.Atoi(s)
+		if err != nil {
+			return f, err
+		}
+	}
+
+	if s := q.Get("from"); s != "" {
+		f.From, err = time.ParseInLocation(auditDateLayout, s, time.Local)
+		if err != nil {
+			return f, err
+		}
+	}
+
+	if s := q.Get("to"); s != "" {
+		f.To, err = time.ParseInLocation(auditDateLayout, s, time.Local)
+		if err != nil {
+			return f, err
+		}
+		f.To = f.To.AddDate(0, 0, 1)
+	}
+
+	return f, nil
+}
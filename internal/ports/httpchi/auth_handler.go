@@ -0,0 +1,211 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/user"
+	"documentation-mini-app/internal/views/htmlview"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+)
+
+const (
+	userCookie = "user"
+	// userHeader is set by authenticating reverse proxy.
+	userHeader = "X-Remote-User"
+)
+
+type AuthUsecase interface {
+	Login(ctx context.Context, name, password string) (string, error)
+	User(ctx context.Context, session string) (*user.User, error)
+}
+
+// loginPage is the data of login template.
+type loginPage struct {
+	Next   string
+	Name   string
+	Failed bool
+}
+
+// AuthHandler identifies editors by signed session cookie issued after login with password or by X-Remote-User
+// header set by trusted reverse proxy.
+type AuthHandler struct {
+	uc      AuthUsecase
+	views   *htmlview.Registry
+	proxies []netip.Prefix
+}
+
+func NewAuthHandler(uc AuthUsecase, views *htmlview.Registry, proxies []netip.Prefix) *AuthHandler {
+	return &AuthHandler{uc: uc, views: views, proxies: proxies}
+}
+
+// ParseProxies parses addresses and CIDR ranges of trusted proxies.
+func ParseProxies(list []string) ([]netip.Prefix, error) {
+	res := make([]netip.Prefix, 0, len(list))
+	for _, s := range list {
+		if !strings.Contains(s, "/") {
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return nil, fmt.Errorf("trusted proxy %q: %w", s, err)
+			}
+			res = append(res, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", s, err)
+		}
+		res = append(res, p.Masked())
+	}
+
+	return res, nil
+}
+
+func (h *AuthHandler) SetupRoutes(r chi.Router) {
+	r.Get("/login", h.GetLogin())
+	r.Post("/login", h.Login())
+	r.Post("/logout", h.Logout())
+}
+
+// UserMiddleware puts user of request into its context.
+func (h *AuthHandler) UserMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var u *user.User
+		if name := r.Header.Get(userHeader); name != "" && h.fromProxy(r) {
+			u = &user.User{Name: name}
+		} else if c, err := r.Cookie(userCookie); err == nil {
+			u, err = h.uc.User(r.Context(), c.Value)
+			if err != nil {
+				u = nil
+			}
+		}
+
+		if u != nil {
+			r = r.WithContext(user.NewContext(r.Context(), u))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// fromProxy reports whether request is sent by trusted proxy.
+func (h *AuthHandler) fromProxy(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, p := range h.proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RequireUser redirects anonymous users to login page.
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !user.IsEditor(r.Context()) {
+			target := r.URL.Path
+			if r.Method != http.MethodGet {
+				ref, err := url.Parse(r.Referer())
+				if err == nil {
+					target = ref.Path
+				}
+			}
+
+			http.Redirect(w, r, "/login?next="+url.QueryEscape(target), http.StatusSeeOther)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (h *AuthHandler) GetLogin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.views.Render(r.Context(), w, "login", loginPage{Next: r.URL.Query().Get("next")})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func (h *AuthHandler) Login() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var b strings.Builder
+		_, err := io.Copy(&b, r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer r.Body.Close()
+
+		q, err := url.ParseQuery(b.String())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		name := strings.TrimSpace(q.Get("name"))
+		session, err := h.uc.Login(r.Context(), name, q.Get("password"))
+		if errors.Is(err, user.ErrInvalidCredentials) {
+			w.WriteHeader(http.StatusUnauthorized)
+			err = h.views.Render(r.Context(), w, "login", loginPage{Next: q.Get("next"), Name: name, Failed: true})
+			if err != nil {
+				log.Println(err)
+			}
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     userCookie,
+			Value:    session,
+			Path:     "/",
+			MaxAge:   int(user.SessionTTL.Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		next := q.Get("next")
+		if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") {
+			next = "/"
+		}
+
+		http.Redirect(w, r, next, http.StatusSeeOther)
+	}
+}
+
+func (h *AuthHandler) Logout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{
+			Name:   userCookie,
+			Path:   "/",
+			MaxAge: -1,
+		})
+
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,95 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/user"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionAuth accepts only session "valid" of alice.
+type sessionAuth struct{}
+
+func (sessionAuth) Login(context.Context, string, string) (string, error) {
+	return "", user.ErrInvalidCredentials
+}
+
+func (sessionAuth) User(_ context.Context, session string) (*user.User, error) {
+	if session != "valid" {
+		return nil, user.ErrInvalidSession
+	}
+
+	return &user.User{Name: "alice"}, nil
+}
+
+func TestUserMiddleware(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.1", "192.168.0.0/16"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     string
+		cookie     string
+		want       string
+	}{
+		{name: "anonymous", remoteAddr: "10.0.0.1:1234"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.1:1234", header: "bob", want: "bob"},
+		{name: "trusted range", remoteAddr: "192.168.1.5:1234", header: "bob", cookie: "valid", want: "bob"},
+		{name: "untrusted header", remoteAddr: "10.0.0.2:1234", header: "bob"},
+		{name: "untrusted header with session", remoteAddr: "10.0.0.2:1234", header: "bob", cookie: "valid",
+			want: "alice"},
+		{name: "session", remoteAddr: "10.0.0.2:1234", cookie: "valid", want: "alice"},
+		{name: "forged session", remoteAddr: "10.0.0.2:1234", cookie: "bob"},
+	}
+
+	for _, tt := range tests {
+		h := NewAuthHandler(sessionAuth{}, nil, proxies)
+
+		got := ""
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if u, ok := user.FromContext(r.Context()); ok {
+				got = u.Name
+			}
+		})
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tt.remoteAddr
+		if tt.header != "" {
+			r.Header.Set(userHeader, tt.header)
+		}
+		if tt.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: userCookie, Value: tt.cookie})
+		}
+
+		h.UserMiddleware(next).ServeHTTP(httptest.NewRecorder(), r)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+}
+
+func TestUserMiddleware_NoProxies(t *testing.T) {
+	h := NewAuthHandler(sessionAuth{}, nil, nil)
+
+	got := false
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(userHeader, "bob")
+	h.UserMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = user.IsEditor(r.Context())
+	})).ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.False(t, got)
+}
+
+func TestParseProxies(t *testing.T) {
+	proxies, err := ParseProxies([]string{"::1", "10.1.2.3/8"})
+	require.NoError(t, err)
+	assert.Equal(t, "::1/128", proxies[0].String())
+	assert.Equal(t, "10.0.0.0/8", proxies[1].String())
+
+	_, err = ParseProxies([]string{"proxy.local"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/check"
+	"documentation-mini-app/internal/views/htmlview"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"log"
+	"net/http"
+	"strings"
+)
+
+type CheckUsecase interface {
+	Check(ctx context.Context) (*check.Report, error)
+}
+
+type CheckHandler struct {
+	uc CheckUsecase
+
+	views *htmlview.Registry
+}
+
+func NewCheckHandler(uc CheckUsecase, views *htmlview.Registry) *CheckHandler {
+	return &CheckHandler{uc: uc, views: views}
+}
+
+func (h *CheckHandler) SetupRoutes(r chi.Router) {
+	r.With(RequireUser).Get("/admin/check", h.GetCheck())
+}
+
+// GetCheck renders consistency report. Report is written as JSON if format=json is set or JSON is accepted.
+func (h *CheckHandler) GetCheck() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report, err := h.uc.Check(r.Context())
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+
+			err = json.NewEncoder(w).Encode(report)
+			if err != nil {
+				log.Println(err)
+			}
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "admin/check", report)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/comment"
+	"documentation-mini-app/internal/views/htmlview"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type CommentUsecase interface {
+	StartThread(ctx context.Context, artID int, exaID int, body string) (*comment.Thread, error)
+	Reply(ctx context.Context, threadID int, body string) (*comment.Thread, error)
+	SetResolved(ctx context.Context, threadID int, resolved bool) (*comment.Thread, error)
+	GetOpenDocThreads(ctx context.Context, docID int) ([]comment.Thread, error)
+}
+
+// docThreadsPage is the data of open threads template.
+type docThreadsPage struct {
+	DocID   int
+	Threads []comment.Thread
+}
+
+type CommentHandler struct {
+	uc CommentUsecase
+
+	views *htmlview.Registry
+}
+
+func NewCommentHandler(uc CommentUsecase, views *htmlview.Registry) *CommentHandler {
+	return &CommentHandler{uc: uc, views: views}
+}
+
+func (h *CommentHandler) SetupRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(RequireUser)
+
+		r.Post("/articles/{articleID}/threads", h.StartThread())
+		r.Get("/documentations/{docID}/threads", h.GetOpenDocThreads())
+
+		r.Route("/threads/{threadID}", func(r chi.Router) {
+			r.Post("/comments", h.Reply())
+			r.Post("/resolve", h.SetResolved(true))
+			r.Post("/unresolve", h.SetResolved(false))
+		})
+	})
+}
+
+// threadURL returns address of thread on its article page.
+func threadURL(t *comment.Thread) string {
+	return fmt.Sprintf("/articles/%v#thread-%v", t.ArticleID, t.ID)
+}
+
+func parseForm(r *http.Request) (url.Values, error) {
+	var b strings.Builder
+	_, err := io.Copy(&b, r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	return url.ParseQuery(b.String())
+}
+
+func (h *CommentHandler) StartThread() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		artID, err := strconv.Atoi(chi.URLParam(r, "articleID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		q, err := parseForm(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		exaID := 0
+		if s := q.Get("example_id"); s != "" {
+			exaID, err = strconv.Atoi(s)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		t, err := h.uc.StartThread(r.Context(), artID, exaID, q.Get("body"))
+		if errors.Is(err, comment.ErrEmptyBody) || errors.Is(err, comment.ErrAlienExample) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, threadURL(t), http.StatusSeeOther)
+	}
+}
+
+func (h *CommentHandler) Reply() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		threadID, err := strconv.Atoi(chi.URLParam(r, "threadID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		q, err := parseForm(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		t, err := h.uc.Reply(r.Context(), threadID, q.Get("body"))
+		if errors.Is(err, comment.ErrEmptyBody) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, threadURL(t), http.StatusSeeOther)
+	}
+}
+
+func (h *CommentHandler) SetResolved(resolved bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		threadID, err := strconv.Atoi(chi.URLParam(r, "threadID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		t, err := h.uc.SetResolved(r.Context(), threadID, resolved)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, threadURL(t), http.StatusSeeOther)
+	}
+}
+
+func (h *CommentHandler) GetOpenDocThreads() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docID, err := strconv.Atoi(chi.URLParam(r, "docID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		threads, err := h.uc.GetOpenDocThreads(r.Context(), docID)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "comments/doc_threads", docThreadsPage{DocID: docID, Threads: threads})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/views/htmlview"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	errNoVersion  = errors.New("version is required, send If-Match header or version field")
+	errBadVersion = errors.New("malformed version")
+	// errWeakVersion is returned for weak entity tag, it never matches in If-Match.
+	errWeakVersion = errors.New("weak entity tag doesn't match current version")
+)
+
+// etag returns entity tag of content version.
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// requestVersion returns content version the client started editing from. It is taken from If-Match header
+// for API clients or from version field of form. fromHeader reports whether If-Match header was used.
+// If-Match uses strong comparison, so weak entity tag is rejected with errWeakVersion.
+func requestVersion(r *http.Request, q url.Values) (version int, fromHeader bool, err error) {
+	raw := q.Get("version")
+	if m := r.Header.Get("If-Match"); m != "" {
+		raw = strings.TrimSpace(m)
+		fromHeader = true
+
+		if strings.HasPrefix(raw, "W/") {
+			return 0, fromHeader, errWeakVersion
+		}
+
+		raw, err = strconv.Unquote(raw)
+		if err != nil {
+			return 0, fromHeader, errBadVersion
+		}
+	}
+
+	if raw == "" {
+		return 0, fromHeader, errNoVersion
+	}
+
+	version, err = strconv.Atoi(raw)
+	if err != nil || version < 1 {
+		return 0, fromHeader, errBadVersion
+	}
+
+	return version, fromHeader, nil
+}
+
+// writeVersionError answers with status matching error of requestVersion.
+func writeVersionError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errNoVersion) {
+		http.Error(w, err.Error(), http.StatusPreconditionRequired)
+		return
+	}
+	if errors.Is(err, errWeakVersion) {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+// conflictField is one field of content edited concurrently.
+type conflictField struct {
+	Name string
+	// Label is message key of field title.
+	Label string
+	// Mine is value sent by user, Theirs is value saved by someone else.
+	Mine   string
+	Theirs string
+	// Long fields are edited in textarea.
+	Long bool
+	// ReadOnly fields are only shown, value of user is sent back in hidden fields.
+	ReadOnly bool
+}
+
+func (f conflictField) Changed() bool {
+	return f.Mine != f.Theirs
+}
+
+// conflictHidden is form value sent back unchanged when user saves merged content.
+type conflictHidden struct {
+	Name  string
+	Value string
+}
+
+// conflictPage is the data of conflict template. It shows both versions of content
+// and form to save merged content on top of current version.
+type conflictPage struct {
+	Title  string
+	Action string
+	Back   string
+	// Version is current version of content, merged content is saved on top of it.
+	Version int
+	Fields  []conflictField
+	Hidden  []conflictHidden
+}
+
+// writeConflict renders conflict page with 412 status for If-Match requests and 409 for forms.
+func writeConflict(ctx context.Context, w http.ResponseWriter, views *htmlview.Registry, fromHeader bool,
+	p conflictPage,
+) {
+	w.Header().Set("ETag", etag(p.Version))
+	if fromHeader {
+		w.WriteHeader(http.StatusPreconditionFailed)
+	} else {
+		w.WriteHeader(http.StatusConflict)
+	}
+
+	err := views.Render(ctx, w, "conflict", p)
+	if err != nil {
+		log.Println(err)
+	}
+}
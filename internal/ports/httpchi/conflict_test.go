@@ -0,0 +1,129 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/review"
+	"documentation-mini-app/internal/domain/user"
+	"documentation-mini-app/internal/i18n"
+	"documentation-mini-app/internal/views/htmlview"
+	"documentation-mini-app/templates"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testViews(t *testing.T) *htmlview.Registry {
+	bundle, err := i18n.New()
+	require.NoError(t, err)
+
+	views, err := htmlview.NewRegistry(templates.FS, bundle)
+	require.NoError(t, err)
+
+	return views
+}
+
+func TestEtag(t *testing.T) {
+	assert.Equal(t, `"3"`, etag(3))
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("If-Match", etag(3))
+	version, fromHeader, err := requestVersion(r, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+	assert.True(t, fromHeader)
+}
+
+func TestRequestVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		ifMatch    string
+		form       string
+		want       int
+		fromHeader bool
+		err        error
+	}{
+		{name: "form", form: "2", want: 2},
+		{name: "header", ifMatch: `"5"`, form: "2", want: 5, fromHeader: true},
+		{name: "weak header", ifMatch: ` W/"5" `, fromHeader: true, err: errWeakVersion},
+		{name: "missing", err: errNoVersion},
+		{name: "unquoted header", ifMatch: "5", fromHeader: true, err: errBadVersion},
+		{name: "not a number", form: "x", err: errBadVersion},
+		{name: "zero", form: "0", err: errBadVersion},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		if tt.ifMatch != "" {
+			r.Header.Set("If-Match", tt.ifMatch)
+		}
+
+		version, fromHeader, err := requestVersion(r, url.Values{"version": {tt.form}})
+		assert.ErrorIs(t, err, tt.err, tt.name)
+		assert.Equal(t, tt.want, version, tt.name)
+		assert.Equal(t, tt.fromHeader, fromHeader, tt.name)
+	}
+}
+
+func TestWriteConflict(t *testing.T) {
+	views := testViews(t)
+	mine := &article.Article{ID: 1, Name: "Maps", Description: "my text", Tags: []string{"go"}}
+	theirs := &article.Article{ID: 1, Name: "Maps", Description: "their text", Version: 4}
+
+	w := httptest.NewRecorder()
+	writeConflict(context.Background(), w, views, false, articleConflict(mine, theirs))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	body := w.Body.String()
+	assert.Contains(t, body, `action="/articles/1/edit"`)
+	assert.Contains(t, body, `name="version" type="hidden" value="4"`)
+	assert.Contains(t, body, "my text")
+	assert.Contains(t, body, "their text")
+
+	w = httptest.NewRecorder()
+	writeConflict(context.Background(), w, views, true, articleConflict(mine, theirs))
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+// conflictReview fails publication of article revision with conflict.
+type conflictReview struct{ ReviewUsecase }
+
+func (conflictReview) PublishRevision(context.Context, int) error {
+	return article.ErrConflict
+}
+
+func (conflictReview) GetRevision(_ context.Context, id int) (*review.Revision, error) {
+	return &review.Revision{
+		ID: id, EntityType: review.EntityArticle, EntityID: 1,
+		Article: &article.Article{Name: "Maps", Description: "revision text", Version: 2},
+	}, nil
+}
+
+func (conflictReview) GetPublished(context.Context, *review.Revision) (*article.Article, *example.Example, error) {
+	return &article.Article{ID: 1, Name: "Maps", Description: "published text", Version: 3}, nil, nil
+}
+
+func TestReviewHandler_PublishConflict(t *testing.T) {
+	router := chi.NewRouter()
+	NewReviewHandler(conflictReview{}, testViews(t)).SetupRoutes(router)
+
+	r := httptest.NewRequest(http.MethodPost, "/revisions/7/publish", nil)
+	r = r.WithContext(user.NewContext(r.Context(), &user.User{Name: "bob"}))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	body := w.Body.String()
+	assert.Contains(t, body, "revision text")
+	assert.Contains(t, body, "published text")
+	assert.Contains(t, body, `action="/articles/1/edit"`)
+	assert.Contains(t, body, `href="/revisions/7"`)
+}
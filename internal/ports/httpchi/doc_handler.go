@@ -1,9 +1,13 @@
 package httpchi
 
 import (
+	"bytes"
 	"context"
+	"documentation-mini-app/internal/domain/article"
 	"documentation-mini-app/internal/domain/doc"
 	"documentation-mini-app/internal/views/htmlview"
+	"documentation-mini-app/internal/views/pdfview"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"io"
@@ -16,46 +20,72 @@ import (
 
 type DocUsecase interface {
 	GetDocByID(ctx context.Context, docID int) (*doc.Documentation, error)
+	GetDocBySlug(ctx context.Context, s string) (*doc.Documentation, error)
 	CreateDoc(ctx context.Context, d *doc.Documentation) error
 	UpdateDoc(ctx context.Context, d *doc.Documentation) error
-	DeleteDoc(ctx context.Context, docID int) error
+	GetDeletePlan(ctx context.Context, docID int) (*doc.DeletePlan, error)
+	DeleteDoc(ctx context.Context, docID int, cascade string) error
+
+	PublishVersion(ctx context.Context, docID int, name string) (*doc.Version, error)
+	GetVersion(ctx context.Context, docID int, name string) (*doc.Version, error)
+	GetLatestVersion(ctx context.Context, docID int) (*doc.Version, error)
+	GetExport(ctx context.Context, docID int, name string) (*doc.Version, error)
+	FilterByTag(ctx context.Context, d *doc.Documentation, t string) error
+}
+
+// docPage is the data of documentation draft template.
+type docPage struct {
+	*doc.Documentation
+	Tag string
+}
+
+// versionArticlePage is the data of article page in documentation version.
+type versionArticlePage struct {
+	Version *doc.Version
+	Article *article.Article
 }
 
 type DocHandler struct {
 	uc DocUsecase
 
-	getDV    *htmlview.TemplateView
-	createDV *htmlview.TemplateView
-	editDV   *htmlview.TemplateView
-	deleteDV *htmlview.TemplateView
+	views *htmlview.Registry
 }
 
-func NewDocHandler(uc DocUsecase,
-	getDocView *htmlview.TemplateView, createDocView *htmlview.TemplateView,
-	editDocView *htmlview.TemplateView, deleteDocView *htmlview.TemplateView,
-) *DocHandler {
-	return &DocHandler{uc: uc,
-		createDV: createDocView, getDV: getDocView,
-		editDV: editDocView, deleteDV: deleteDocView}
+func NewDocHandler(uc DocUsecase, views *htmlview.Registry) *DocHandler {
+	return &DocHandler{uc: uc, views: views}
 }
 
 func (h *DocHandler) SetupRoutes(r chi.Router) {
+	r.Get("/docs/{docSlug}", h.GetDocBySlug())
+
 	r.Route("/documentations", func(r chi.Router) {
-		r.Get("/create", h.GetCreateDoc())
-		r.Post("/create", h.CreateDoc())
+		r.With(RequireUser).Get("/create", h.GetCreateDoc())
+		r.With(RequireUser).Post("/create", h.CreateDoc())
 
 		r.Route("/{docID}", func(r chi.Router) {
 			r.Get("/", h.GetDoc())
+			r.Get("/draft", h.GetDraftDoc())
+
+			r.Get("/v/{version}", h.GetVersion())
+			r.Get("/v/{version}/articles/{articleID}", h.GetVersionArticle())
+			r.Get("/export", h.ExportDoc())
+
+			r.Group(func(r chi.Router) {
+				r.Use(RequireUser)
 
-			r.Get("/edit", h.GetEditDoc())
-			r.Post("/edit", h.EditDoc())
+				r.Post("/versions", h.PublishVersion())
 
-			r.Get("/delete", h.GetDeleteDoc())
-			r.Post("/delete", h.DeleteDoc())
+				r.Get("/edit", h.GetEditDoc())
+				r.Post("/edit", h.EditDoc())
+
+				r.Get("/delete", h.GetDeleteDoc())
+				r.Post("/delete", h.DeleteDoc())
+			})
 		})
 	})
 }
 
+// GetDoc redirects to documentation URL with slug. Documentation without slug is rendered in place.
 func (h *DocHandler) GetDoc() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		docID, err := strconv.Atoi(chi.URLParam(r, "docID"))
@@ -66,23 +96,117 @@ func (h *DocHandler) GetDoc() http.HandlerFunc {
 		}
 
 		d, err := h.uc.GetDocByID(r.Context(), docID)
+		if errors.Is(err, doc.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if d.Slug != "" {
+			redirectPermanent(w, r, docPath(d.Slug))
+			return
+		}
+
+		h.writeDoc(w, r, d)
+	}
+}
+
+// GetDocBySlug renders documentation by slug. Documentation found by its previous slug is redirected to current URL.
+func (h *DocHandler) GetDocBySlug() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s := chi.URLParam(r, "docSlug")
+
+		d, err := h.uc.GetDocBySlug(r.Context(), s)
+		if errors.Is(err, doc.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if d.Slug != s {
+			redirectPermanent(w, r, docPath(d.Slug))
+			return
+		}
+
+		h.writeDoc(w, r, d)
+	}
+}
+
+// writeDoc writes the latest version of documentation or its draft if documentation has no versions.
+func (h *DocHandler) writeDoc(w http.ResponseWriter, r *http.Request, d *doc.Documentation) {
+	v, err := h.uc.GetLatestVersion(r.Context(), d.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if v == nil {
+		h.writeDraft(w, r, d)
+		return
+	}
+
+	err = h.views.Render(r.Context(), w, "docs/get_doc_version", v)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *DocHandler) GetDraftDoc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docID, err := strconv.Atoi(chi.URLParam(r, "docID"))
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		err = h.getDV.ToWriter(w, d)
+		d, err := h.uc.GetDocByID(r.Context(), docID)
+		if errors.Is(err, doc.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
+
+		h.writeDraft(w, r, d)
+	}
+}
+
+// writeDraft writes current state of documentation, filtered by tag from query.
+func (h *DocHandler) writeDraft(w http.ResponseWriter, r *http.Request, d *doc.Documentation) {
+	t := r.URL.Query().Get("tag")
+	if t != "" {
+		err := h.uc.FilterByTag(r.Context(), d, t)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err := h.views.Render(r.Context(), w, "docs/get_doc", docPage{Documentation: d, Tag: t})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
 func (h *DocHandler) GetCreateDoc() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		err := h.createDV.ToWriter(w, struct{}{})
+		err := h.views.Render(r.Context(), w, "docs/create_doc", struct{}{})
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -137,13 +261,18 @@ func (h *DocHandler) GetEditDoc() http.HandlerFunc {
 		}
 
 		d, err := h.uc.GetDocByID(r.Context(), docID)
+		if errors.Is(err, doc.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			log.Println(err)
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		err = h.editDV.ToWriter(w, d)
+		w.Header().Set("ETag", etag(d.Version))
+		err = h.views.Render(r.Context(), w, "docs/edit_doc", d)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -181,19 +310,50 @@ func (h *DocHandler) EditDoc() http.HandlerFunc {
 			return
 		}
 
+		version, fromHeader, err := requestVersion(r, q)
+		if err != nil {
+			writeVersionError(w, err)
+			return
+		}
+
 		d := doc.Documentation{
-			ID:   docID,
-			Name: name,
+			ID:      docID,
+			Name:    name,
+			Version: version,
 		}
 
 		err = h.uc.UpdateDoc(r.Context(), &d)
+		if errors.Is(err, doc.ErrConflict) {
+			h.writeConflict(w, r, fromHeader, &d)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		http.Redirect(w, r, fmt.Sprintf("/documentations/%v", d.ID), http.StatusSeeOther)
+		http.Redirect(w, r, fmt.Sprintf("/documentations/%v/draft", d.ID), http.StatusSeeOther)
+	}
+}
+
+// writeConflict renders page to merge documentation edited by user with documentation saved by someone else.
+func (h *DocHandler) writeConflict(w http.ResponseWriter, r *http.Request, fromHeader bool, mine *doc.Documentation) {
+	theirs, err := h.uc.GetDocByID(r.Context(), mine.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
+
+	writeConflict(r.Context(), w, h.views, fromHeader, conflictPage{
+		Title:   theirs.Name,
+		Action:  fmt.Sprintf("/documentations/%v/edit", mine.ID),
+		Back:    fmt.Sprintf("/documentations/%v/draft", mine.ID),
+		Version: theirs.Version,
+		Fields: []conflictField{
+			{Name: "name", Label: "form.name", Mine: mine.Name, Theirs: theirs.Name},
+		},
+	})
 }
 
 func (h *DocHandler) GetDeleteDoc() http.HandlerFunc {
@@ -205,14 +365,14 @@ func (h *DocHandler) GetDeleteDoc() http.HandlerFunc {
 			return
 		}
 
-		d, err := h.uc.GetDocByID(r.Context(), docID)
+		plan, err := h.uc.GetDeletePlan(r.Context(), docID)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		err = h.deleteDV.ToWriter(w, d)
+		err = h.views.Render(r.Context(), w, "docs/delete_doc", plan)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -229,7 +389,22 @@ func (h *DocHandler) DeleteDoc() http.HandlerFunc {
 			return
 		}
 
-		err = h.uc.DeleteDoc(r.Context(), docID)
+		q, err := parseForm(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		cascade := q.Get("cascade")
+		if cascade == "" {
+			cascade = doc.CascadeUnlink
+		}
+
+		err = h.uc.DeleteDoc(r.Context(), docID, cascade)
+		if errors.Is(err, doc.ErrUnknownCascade) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -238,3 +413,189 @@ func (h *DocHandler) DeleteDoc() http.HandlerFunc {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 	}
 }
+
+func (h *DocHandler) PublishVersion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docID, err := strconv.Atoi(chi.URLParam(r, "docID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var b strings.Builder
+		_, err = io.Copy(&b, r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer r.Body.Close()
+
+		q, err := url.ParseQuery(b.String())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		v, err := h.uc.PublishVersion(r.Context(), docID, q.Get("name"))
+		if errors.Is(err, doc.ErrInvalidVersionName) || errors.Is(err, doc.ErrVersionExists) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("/documentations/%v/v/%v", docID, url.PathEscape(v.Name)), http.StatusSeeOther)
+	}
+}
+
+func (h *DocHandler) GetVersion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docID, err := strconv.Atoi(chi.URLParam(r, "docID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		v, err := h.uc.GetVersion(r.Context(), docID, chi.URLParam(r, "version"))
+		if errors.Is(err, doc.ErrVersionNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "docs/get_doc_version", v)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func (h *DocHandler) GetVersionArticle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docID, err := strconv.Atoi(chi.URLParam(r, "docID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		artID, err := strconv.Atoi(chi.URLParam(r, "articleID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		v, err := h.uc.GetVersion(r.Context(), docID, chi.URLParam(r, "version"))
+		if errors.Is(err, doc.ErrVersionNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		art, ok := v.Doc.ArticleByID(artID)
+		if !ok {
+			http.Error(w, "article not found in this version", http.StatusNotFound)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "docs/get_version_article", versionArticlePage{Version: v, Article: art})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// ExportDoc writes offline copy of documentation version from query, current published content by default,
+// as PDF file or print-ready HTML page.
+func (h *DocHandler) ExportDoc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docID, err := strconv.Atoi(chi.URLParam(r, "docID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format != "pdf" && format != "html" {
+			http.Error(w, "unknown export format", http.StatusBadRequest)
+			return
+		}
+
+		name := r.URL.Query().Get("version")
+		v, err := h.uc.GetExport(r.Context(), docID, name)
+		if errors.Is(err, doc.ErrNotFound) || errors.Is(err, doc.ErrVersionNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if format == "html" {
+			err = h.views.Render(r.Context(), w, "docs/print_doc", v)
+			if err != nil {
+				log.Println(err)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		subtitle := h.views.T(r.Context(), "export.current")
+		if v.Name != "" {
+			subtitle = h.views.T(r.Context(), "export.version", v.Name)
+		}
+
+		var b bytes.Buffer
+		err = pdfview.DocToWriter(&b, v.Doc, pdfview.Labels{
+			Contents: h.views.T(r.Context(), "export.contents"),
+			Examples: h.views.T(r.Context(), "export.examples"),
+			Output:   h.views.T(r.Context(), "export.output"),
+			Subtitle: subtitle,
+		})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="documentation-%v.pdf"`, docID))
+
+		_, err = b.WriteTo(w)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// docPath returns URL path of documentation with slug.
+func docPath(docSlug string) string {
+	return "/docs/" + url.PathEscape(docSlug)
+}
+
+// redirectPermanent redirects to path keeping query of request.
+func redirectPermanent(w http.ResponseWriter, r *http.Request, path string) {
+	if r.URL.RawQuery != "" {
+		path += "?" + r.URL.RawQuery
+	}
+
+	http.Redirect(w, r, path, http.StatusMovedPermanently)
+}
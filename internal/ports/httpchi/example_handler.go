@@ -1,9 +1,16 @@
 package httpchi
 
 import (
+	"bytes"
 	"context"
+	"documentation-mini-app/internal/domain/article"
 	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/review"
+	"documentation-mini-app/internal/domain/tag"
 	"documentation-mini-app/internal/views/htmlview"
+	"documentation-mini-app/internal/views/zipview"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"io"
@@ -16,48 +23,66 @@ import (
 
 type ExampleUsecase interface {
 	GetExampleByID(ctx context.Context, id int) (*example.Example, error)
-	CreateExample(ctx context.Context, exa *example.Example) error
-	AddExampleToArticle(ctx context.Context, exaID int, artID int) error
-	UpdateExample(ctx context.Context, exa *example.Example) error
+	GetExampleForEdit(ctx context.Context, id int) (*example.Example, error)
+	CreateExample(ctx context.Context, exa *example.Example, artID int, allowDuplicate bool) error
+	LinkExample(ctx context.Context, exaID int, artID int) error
+	SaveExampleDraft(ctx context.Context, exa *example.Example) (*review.Revision, error)
 	DeleteExample(ctx context.Context, id int) error
 }
 
+// exampleForm is the data of create and edit example templates.
+type exampleForm struct {
+	*example.Example
+	Error string
+	// Duplicates are existing examples with the same code, offered to link instead of creating new one.
+	Duplicates []example.Example
+	ArticleID  int
+}
+
+// duplicateJSON is existing example with the same code in API answer.
+type duplicateJSON struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	LinkURL string `json:"link_url"`
+}
+
 type ExampleHandler struct {
 	uc ExampleUsecase
 
-	getEV    *htmlview.TemplateView
-	createEV *htmlview.TemplateView
-	editEV   *htmlview.TemplateView
-	deleteEV *htmlview.TemplateView
+	views *htmlview.Registry
 }
 
-func NewExampleHandler(uc ExampleUsecase,
-	getExampleView *htmlview.TemplateView, createExampleView *htmlview.TemplateView,
-	editExampleView *htmlview.TemplateView, deleteExampleView *htmlview.TemplateView,
-) *ExampleHandler {
-	return &ExampleHandler{uc: uc,
-		getEV: getExampleView, createEV: createExampleView,
-		editEV: editExampleView, deleteEV: deleteExampleView,
-	}
+func NewExampleHandler(uc ExampleUsecase, views *htmlview.Registry) *ExampleHandler {
+	return &ExampleHandler{uc: uc, views: views}
 }
 
 func (h *ExampleHandler) SetupRoutes(r chi.Router) {
 	r.Route("/examples", func(r chi.Router) {
 		r.Route("/{exaID}", func(r chi.Router) {
 			r.Get("/", h.GetExample())
+			r.Get("/download", h.DownloadExample())
 
-			r.Get("/edit", h.GetEditExample())
-			r.Post("/edit", h.EditExample())
+			r.Group(func(r chi.Router) {
+				r.Use(RequireUser)
 
-			r.Get("/delete", h.GetDeleteExample())
-			r.Post("/delete", h.DeleteExample())
+				r.Get("/edit", h.GetEditExample())
+				r.Post("/edit", h.EditExample())
+
+				r.Get("/delete", h.GetDeleteExample())
+				r.Post("/delete", h.DeleteExample())
+			})
 		})
 	})
 
 	r.Route("/articles/{artID}/examples/create", func(r chi.Router) {
+		r.Use(RequireUser)
+
 		r.Get("/", h.GetCreateExample())
 		r.Post("/", h.CreateExample())
 	})
+
+	r.With(RequireUser).Post("/articles/{artID}/examples/link", h.LinkExample())
 }
 
 func (h *ExampleHandler) GetExample() http.HandlerFunc {
@@ -70,23 +95,67 @@ func (h *ExampleHandler) GetExample() http.HandlerFunc {
 		}
 
 		d, err := h.uc.GetExampleByID(r.Context(), exaID)
+		if errors.Is(err, example.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "examples/get_example", d)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func (h *ExampleHandler) DownloadExample() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		exaID, err := strconv.Atoi(chi.URLParam(r, "exaID"))
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		err = h.getEV.ToWriter(w, d)
+		exa, err := h.uc.GetExampleByID(r.Context(), exaID)
+		if errors.Is(err, example.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		var b bytes.Buffer
+		err = zipview.ExampleToWriter(&b, exa)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="example-%v.zip"`, exa.ID))
+
+		_, err = b.WriteTo(w)
+		if err != nil {
+			log.Println(err)
 		}
 	}
 }
 
 func (h *ExampleHandler) GetCreateExample() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		err := h.createEV.ToWriter(w, struct{}{})
+		err := h.views.Render(r.Context(), w, "examples/create_example", exampleForm{
+			Example: &example.Example{AutoFormat: true},
+		})
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -119,8 +188,13 @@ func (h *ExampleHandler) CreateExample() http.HandlerFunc {
 
 		name := q.Get("name")
 		desc := q.Get("description")
+		tags := tag.Parse(q.Get("tags"))
 		code := q.Get("code")
 		outp := q.Get("output")
+		lang := q.Get("highlight_language")
+		autoFormat := q.Get("auto_format") != ""
+		allowDuplicate := q.Get("allow_duplicate") != ""
+		files, filesErr := parseExampleFiles(q)
 
 		if name == "" {
 			http.Error(w, "name can't be empty", http.StatusBadRequest)
@@ -130,24 +204,83 @@ func (h *ExampleHandler) CreateExample() http.HandlerFunc {
 		exa := example.Example{
 			Name:        name,
 			Description: desc,
+			Tags:        tags,
 			Code:        code,
 			Output:      outp,
 			Priority:    0,
+
+			HighlightLanguage: lang,
+			AutoFormat:        autoFormat,
+			Files:             files,
+		}
+
+		if filesErr != nil {
+			h.writeFormError(w, r, "examples/create_example", &exa, filesErr)
+			return
 		}
 
-		err = h.uc.CreateExample(r.Context(), &exa)
+		err = h.uc.CreateExample(r.Context(), &exa, artID, allowDuplicate)
+		if errors.Is(err, article.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, example.ErrInvalidCode) || errors.Is(err, example.ErrInvalidFiles) {
+			h.writeFormError(w, r, "examples/create_example", &exa, err)
+			return
+		}
+		var dupErr *example.DuplicateError
+		if errors.As(err, &dupErr) {
+			h.writeDuplicates(w, r, &exa, artID, dupErr.Existing)
+			return
+		}
 		if err != nil {
+			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		err = h.uc.AddExampleToArticle(r.Context(), exa.ID, artID)
+		http.Redirect(w, r, fmt.Sprintf("/examples/%v", exa.ID), http.StatusSeeOther)
+	}
+}
+
+// LinkExample adds existing example to article instead of creating its copy.
+func (h *ExampleHandler) LinkExample() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		artID, err := strconv.Atoi(chi.URLParam(r, "artID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		q, err := parseForm(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		exaID, err := strconv.Atoi(q.Get("example_id"))
+		if err != nil {
+			http.Error(w, "invalid example id", http.StatusBadRequest)
+			return
+		}
+
+		err = h.uc.LinkExample(r.Context(), exaID, artID)
+		if errors.Is(err, article.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, example.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err != nil {
+			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		http.Redirect(w, r, fmt.Sprintf("/examples/%v", exa.ID), http.StatusSeeOther)
+		http.Redirect(w, r, fmt.Sprintf("/articles/%v", artID), http.StatusSeeOther)
 	}
 }
 
@@ -160,14 +293,15 @@ func (h *ExampleHandler) GetEditExample() http.HandlerFunc {
 			return
 		}
 
-		exa, err := h.uc.GetExampleByID(r.Context(), exaID)
+		exa, err := h.uc.GetExampleForEdit(r.Context(), exaID)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		err = h.editEV.ToWriter(w, exa)
+		w.Header().Set("ETag", etag(exa.Version))
+		err = h.views.Render(r.Context(), w, "examples/edit_example", exampleForm{Example: exa})
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -200,33 +334,122 @@ func (h *ExampleHandler) EditExample() http.HandlerFunc {
 
 		name := q.Get("name")
 		desc := q.Get("description")
+		tags := tag.Parse(q.Get("tags"))
 		code := q.Get("code")
 		outp := q.Get("output")
+		lang := q.Get("highlight_language")
+		autoFormat := q.Get("auto_format") != ""
+		files, filesErr := parseExampleFiles(q)
 
 		if name == "" {
 			http.Error(w, "name can't be empty", http.StatusBadRequest)
 			return
 		}
 
+		version, fromHeader, err := requestVersion(r, q)
+		if err != nil {
+			writeVersionError(w, err)
+			return
+		}
+
 		exa := example.Example{
 			ID:          exaID,
 			Name:        name,
 			Description: desc,
+			Tags:        tags,
 			Code:        code,
 			Output:      outp,
 			Priority:    0,
+
+			HighlightLanguage: lang,
+			AutoFormat:        autoFormat,
+			Files:             files,
+			Version:           version,
 		}
 
-		err = h.uc.UpdateExample(r.Context(), &exa)
+		if filesErr != nil {
+			h.writeFormError(w, r, "examples/edit_example", &exa, filesErr)
+			return
+		}
+
+		rev, err := h.uc.SaveExampleDraft(r.Context(), &exa)
+		if errors.Is(err, example.ErrInvalidCode) || errors.Is(err, example.ErrInvalidFiles) {
+			h.writeFormError(w, r, "examples/edit_example", &exa, err)
+			return
+		}
+		if errors.Is(err, example.ErrConflict) {
+			h.writeConflict(w, r, fromHeader, &exa)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		http.Redirect(w, r, fmt.Sprintf("/examples/%v", exa.ID), http.StatusSeeOther)
+		http.Redirect(w, r, fmt.Sprintf("/revisions/%v", rev.ID), http.StatusSeeOther)
+	}
+}
+
+// writeConflict renders page to merge example edited by user with example saved by someone else.
+func (h *ExampleHandler) writeConflict(w http.ResponseWriter, r *http.Request, fromHeader bool,
+	mine *example.Example,
+) {
+	theirs, err := h.uc.GetExampleByID(r.Context(), mine.ID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeConflict(r.Context(), w, h.views, fromHeader, exampleConflict(mine, theirs))
+}
+
+// exampleConflict returns conflict page of example edited by user and example saved by someone else.
+// Files and auto format of user are kept as they are.
+func exampleConflict(mine, theirs *example.Example) conflictPage {
+	hidden := make([]conflictHidden, 0, 3*len(mine.Files)+1)
+	if mine.AutoFormat {
+		hidden = append(hidden, conflictHidden{Name: "auto_format", Value: "on"})
+	}
+	for _, f := range mine.Files {
+		hidden = append(hidden,
+			conflictHidden{Name: "file_name", Value: f.Name},
+			conflictHidden{Name: "file_language", Value: f.HighlightLanguage},
+			conflictHidden{Name: "file_code", Value: f.Code})
+	}
+
+	return conflictPage{
+		Title:   theirs.Name,
+		Action:  fmt.Sprintf("/examples/%v/edit", theirs.ID),
+		Back:    fmt.Sprintf("/examples/%v", theirs.ID),
+		Version: theirs.Version,
+		Fields: []conflictField{
+			{Name: "name", Label: "form.name", Mine: mine.Name, Theirs: theirs.Name},
+			{Name: "description", Label: "form.description", Mine: mine.Description, Theirs: theirs.Description,
+				Long: true},
+			{Name: "tags", Label: "form.tags", Mine: strings.Join(mine.Tags, ", "),
+				Theirs: strings.Join(theirs.Tags, ", ")},
+			{Name: "highlight_language", Label: "form.highlight_language", Mine: mine.HighlightLanguage,
+				Theirs: theirs.HighlightLanguage},
+			{Name: "code", Label: "form.code", Mine: mine.Code, Theirs: theirs.Code, Long: true},
+			{Name: "files", Label: "form.files", Mine: filesText(mine.Files), Theirs: filesText(theirs.Files),
+				Long: true, ReadOnly: true},
+			{Name: "output", Label: "form.output", Mine: mine.Output, Theirs: theirs.Output, Long: true},
+		},
+		Hidden: hidden,
 	}
 }
 
+// filesText returns files of example as one text to compare them.
+func filesText(files []example.File) string {
+	var b strings.Builder
+	for _, f := range files {
+		fmt.Fprintf(&b, "// %s\n%s\n", f.Name, f.Code)
+	}
+
+	return b.String()
+}
+
 func (h *ExampleHandler) GetDeleteExample() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		exaID, err := strconv.Atoi(chi.URLParam(r, "exaID"))
@@ -237,13 +460,17 @@ func (h *ExampleHandler) GetDeleteExample() http.HandlerFunc {
 		}
 
 		exa, err := h.uc.GetExampleByID(r.Context(), exaID)
+		if errors.Is(err, example.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
 		if err != nil {
 			log.Println(err)
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 
-		err = h.deleteEV.ToWriter(w, exa)
+		err = h.views.Render(r.Context(), w, "examples/delete_example", exa)
 		if err != nil {
 			log.Println(err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -269,3 +496,95 @@ func (h *ExampleHandler) DeleteExample() http.HandlerFunc {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 	}
 }
+
+// parseExampleFiles parses repeated file_name, file_language and file_code form fields.
+// Files with empty name and code are skipped, names are cleaned by example.CleanFileName.
+func parseExampleFiles(q url.Values) ([]example.File, error) {
+	names := q["file_name"]
+	langs := q["file_language"]
+	codes := q["file_code"]
+
+	if len(names) != len(langs) || len(names) != len(codes) {
+		return nil, errors.New("file fields count mismatch")
+	}
+
+	files := make([]example.File, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for i := range names {
+		if strings.TrimSpace(names[i]) == "" && strings.TrimSpace(codes[i]) == "" {
+			continue
+		}
+
+		name, err := example.CleanFileName(names[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate file name %q", name)
+		}
+		seen[name] = true
+
+		files = append(files, example.File{
+			Name:              name,
+			HighlightLanguage: strings.TrimSpace(langs[i]),
+			Code:              codes[i],
+		})
+	}
+
+	return files, nil
+}
+
+// writeDuplicates answers with 409 and existing examples with the same code: as JSON if it is accepted,
+// otherwise as create form offering to link one of them or to create the example anyway.
+func (h *ExampleHandler) writeDuplicates(w http.ResponseWriter, r *http.Request,
+	exa *example.Example, artID int, existing []example.Example,
+) {
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		res := struct {
+			Error      string          `json:"error"`
+			Duplicates []duplicateJSON `json:"duplicates"`
+		}{Error: "duplicate example", Duplicates: make([]duplicateJSON, 0, len(existing))}
+
+		for _, e := range existing {
+			res.Duplicates = append(res.Duplicates, duplicateJSON{
+				ID:      e.ID,
+				Name:    e.Name,
+				URL:     fmt.Sprintf("/examples/%v", e.ID),
+				LinkURL: fmt.Sprintf("/articles/%v/examples/link", artID),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusConflict)
+
+		err := json.NewEncoder(w).Encode(res)
+		if err != nil {
+			log.Println(err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusConflict)
+
+	err := h.views.Render(r.Context(), w, "examples/create_example", exampleForm{
+		Example:    exa,
+		Duplicates: existing,
+		ArticleID:  artID,
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// writeFormError renders form view again with entered example and validation error.
+func (h *ExampleHandler) writeFormError(w http.ResponseWriter, r *http.Request, page string,
+	exa *example.Example, formErr error,
+) {
+	w.WriteHeader(http.StatusUnprocessableEntity)
+
+	err := h.views.Render(r.Context(), w, page, exampleForm{Example: exa, Error: formErr.Error()})
+	if err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,41 @@
+package httpchi
+
+import (
+	"documentation-mini-app/internal/domain/example"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExampleFiles(t *testing.T) {
+	q := url.Values{
+		"file_name":     {" go.mod ", "", "./cmd/main.go"},
+		"file_language": {"", "", "go"},
+		"file_code":     {"module x", " ", "package main"},
+	}
+
+	files, err := parseExampleFiles(q)
+	require.NoError(t, err)
+	assert.Equal(t, []example.File{
+		{Name: "go.mod", Code: "module x"},
+		{Name: "cmd/main.go", HighlightLanguage: "go", Code: "package main"},
+	}, files)
+}
+
+func TestParseExampleFiles_Invalid(t *testing.T) {
+	tests := map[string]url.Values{
+		"mismatch":  {"file_name": {"a"}, "file_language": {}, "file_code": {"x"}},
+		"no name":   {"file_name": {""}, "file_language": {""}, "file_code": {"x"}},
+		"duplicate": {"file_name": {"a", "./a"}, "file_language": {"", ""}, "file_code": {"x", "y"}},
+		"parent":    {"file_name": {"../../x"}, "file_language": {""}, "file_code": {"x"}},
+		"absolute":  {"file_name": {"/etc/x"}, "file_language": {""}, "file_code": {"x"}},
+		"backslash": {"file_name": {`..\x`}, "file_language": {""}, "file_code": {"x"}},
+	}
+
+	for name, q := range tests {
+		_, err := parseExampleFiles(q)
+		assert.Error(t, err, name)
+	}
+}
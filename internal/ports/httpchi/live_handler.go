@@ -0,0 +1,116 @@
+package httpchi
+
+import (
+	"documentation-mini-app/internal/domain/event"
+	"documentation-mini-app/internal/domain/user"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// liveHeartbeat is interval of comments that keep idle event stream open through proxies.
+const liveHeartbeat = 25 * time.Second
+
+// liveRetry is delay in milliseconds before browser reconnects to closed event stream.
+const liveRetry = 3000
+
+type LiveUsecase interface {
+	Subscribe(docID int, artID int) (<-chan event.Change, func())
+}
+
+// LiveHandler streams changes of documentations and articles as Server-Sent Events. Every change is sent
+// as event "change" with JSON of event.Change. Changes of drafts are sent only to editors.
+type LiveHandler struct {
+	uc LiveUsecase
+}
+
+func NewLiveHandler(uc LiveUsecase) *LiveHandler {
+	return &LiveHandler{uc: uc}
+}
+
+func (h *LiveHandler) SetupRoutes(r chi.Router) {
+	r.Get("/documentations/{docID}/events", h.GetDocEvents())
+	r.Get("/articles/{articleID}/events", h.GetArticleEvents())
+}
+
+func (h *LiveHandler) GetDocEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docID, err := strconv.Atoi(chi.URLParam(r, "docID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		h.stream(w, r, docID, 0)
+	}
+}
+
+func (h *LiveHandler) GetArticleEvents() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		artID, err := strconv.Atoi(chi.URLParam(r, "articleID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		h.stream(w, r, 0, artID)
+	}
+}
+
+// stream writes changes of page until client disconnects.
+func (h *LiveHandler) stream(w http.ResponseWriter, r *http.Request, docID int, artID int) {
+	rc := http.NewResponseController(w)
+
+	// Stream is longer than write timeout of server.
+	err := rc.SetWriteDeadline(time.Time{})
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	editor := user.IsEditor(r.Context())
+
+	changes, cancel := h.uc.Subscribe(docID, artID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintf(w, "retry: %d\n\n", liveRetry)
+
+	ticker := time.NewTicker(liveHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		err = rc.Flush()
+		if err != nil {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			_, _ = fmt.Fprint(w, ": ping\n\n")
+		case c := <-changes:
+			if c.Draft && !editor {
+				continue
+			}
+
+			data, err := json.Marshal(c)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "event: change\ndata: %s\n\n", data)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package httpchi
+
+import (
+	"documentation-mini-app/internal/i18n"
+	"net/http"
+)
+
+const (
+	localeCookie = "lang"
+	// localeMaxAge is how long in seconds chosen language is remembered.
+	localeMaxAge = 365 * 24 * 60 * 60
+)
+
+// LocaleMiddleware puts locale of the request into context. Language chosen with lang query parameter
+// is remembered in cookie, otherwise the cookie and then Accept-Language header are used.
+func LocaleMiddleware(b *i18n.Bundle) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Language, Cookie")
+
+			loc := r.URL.Query().Get("lang")
+			if b.Supported(loc) {
+				http.SetCookie(w, &http.Cookie{
+					Name:     localeCookie,
+					Value:    loc,
+					Path:     "/",
+					MaxAge:   localeMaxAge,
+					SameSite: http.SameSiteLaxMode,
+				})
+			} else if c, err := r.Cookie(localeCookie); err == nil && b.Supported(c.Value) {
+				loc = c.Value
+			} else {
+				loc = b.Match(r.Header.Get("Accept-Language"))
+			}
+
+			next.ServeHTTP(w, r.WithContext(i18n.NewContext(r.Context(), loc)))
+		})
+	}
+}
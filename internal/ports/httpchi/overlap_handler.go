@@ -0,0 +1,61 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/overlap"
+	"documentation-mini-app/internal/views/htmlview"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"log"
+	"net/http"
+)
+
+type OverlapUsecase interface {
+	GetOverlap(ctx context.Context) (*overlap.Report, error)
+}
+
+// overlapPage is the data of overlap template.
+type overlapPage struct {
+	*overlap.Report
+	Threshold float64
+}
+
+// Percent formats similarity from 0 to 1 as percents.
+func (overlapPage) Percent(v float64) string {
+	return fmt.Sprintf("%.0f%%", v*100)
+}
+
+type OverlapHandler struct {
+	uc OverlapUsecase
+
+	views *htmlview.Registry
+}
+
+func NewOverlapHandler(uc OverlapUsecase, views *htmlview.Registry) *OverlapHandler {
+	return &OverlapHandler{uc: uc, views: views}
+}
+
+func (h *OverlapHandler) SetupRoutes(r chi.Router) {
+	r.With(RequireUser).Get("/admin/overlap", h.GetOverlap())
+}
+
+// GetOverlap renders similarity of documentations and near-duplicate articles.
+func (h *OverlapHandler) GetOverlap() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report, err := h.uc.GetOverlap(r.Context())
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "admin/overlap", overlapPage{
+			Report:    report,
+			Threshold: overlap.DuplicateThreshold,
+		})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/review"
+	"documentation-mini-app/internal/domain/user"
+	"documentation-mini-app/internal/views/htmlview"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type ReviewUsecase interface {
+	GetRevision(ctx context.Context, id int) (*review.Revision, error)
+	GetQueue(ctx context.Context) ([]review.Revision, error)
+	SubmitRevision(ctx context.Context, id int) error
+	ApproveRevision(ctx context.Context, id int) error
+	RejectRevision(ctx context.Context, id int) error
+	PublishRevision(ctx context.Context, id int) error
+	GetPublished(ctx context.Context, rev *review.Revision) (*article.Article, *example.Example, error)
+}
+
+// revisionPage is the data of revision template.
+type revisionPage struct {
+	*review.Revision
+	User string
+}
+
+type ReviewHandler struct {
+	uc ReviewUsecase
+
+	views *htmlview.Registry
+}
+
+func NewReviewHandler(uc ReviewUsecase, views *htmlview.Registry) *ReviewHandler {
+	return &ReviewHandler{uc: uc, views: views}
+}
+
+func (h *ReviewHandler) SetupRoutes(r chi.Router) {
+	r.With(RequireUser).Get("/reviews", h.GetQueue())
+
+	r.Route("/revisions/{revID}", func(r chi.Router) {
+		r.Use(RequireUser)
+
+		r.Get("/", h.GetRevision())
+		r.Post("/submit", h.transit(h.uc.SubmitRevision))
+		r.Post("/approve", h.transit(h.uc.ApproveRevision))
+		r.Post("/reject", h.transit(h.uc.RejectRevision))
+		r.Post("/publish", h.transit(h.uc.PublishRevision))
+	})
+}
+
+func (h *ReviewHandler) GetQueue() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		revs, err := h.uc.GetQueue(r.Context())
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "reviews/queue", revs)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func (h *ReviewHandler) GetRevision() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		revID, err := strconv.Atoi(chi.URLParam(r, "revID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rev, err := h.uc.GetRevision(r.Context(), revID)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		u, _ := user.FromContext(r.Context())
+
+		err = h.views.Render(r.Context(), w, "reviews/get_revision", revisionPage{Revision: rev, User: u.Name})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// transit returns handler that performs workflow action with revision and redirects back to it.
+func (h *ReviewHandler) transit(action func(ctx context.Context, id int) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		revID, err := strconv.Atoi(chi.URLParam(r, "revID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = action(r.Context(), revID)
+		if errors.Is(err, review.ErrWrongStatus) || errors.Is(err, review.ErrNotAuthor) ||
+			errors.Is(err, review.ErrSelfApprove) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if errors.Is(err, article.ErrConflict) || errors.Is(err, example.ErrConflict) {
+			h.writeConflict(w, r, revID)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("/revisions/%v", revID), http.StatusSeeOther)
+	}
+}
+
+// writeConflict renders page to merge content of revision with content published after it was saved.
+// Merged content is saved as draft on top of published version.
+func (h *ReviewHandler) writeConflict(w http.ResponseWriter, r *http.Request, revID int) {
+	rev, err := h.uc.GetRevision(r.Context(), revID)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	art, exa, err := h.uc.GetPublished(r.Context(), rev)
+	if err != nil {
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var p conflictPage
+	if art != nil {
+		p = articleConflict(rev.Article, art)
+	} else {
+		p = exampleConflict(rev.Example, exa)
+	}
+	p.Back = fmt.Sprintf("/revisions/%v", revID)
+
+	writeConflict(r.Context(), w, h.views, false, p)
+}
@@ -0,0 +1,75 @@
+package httpchi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+)
+
+// staticMaxAge is how long in seconds browsers may use static asset without revalidation.
+const staticMaxAge = "86400"
+
+// StaticHandler serves CSS and JS under /static. Responses have ETag by content, so after max age
+// browsers revalidate cheaply with If-None-Match.
+type StaticHandler struct {
+	fsys fs.FS
+}
+
+func NewStaticHandler(fsys fs.FS) *StaticHandler {
+	return &StaticHandler{fsys: fsys}
+}
+
+func (h *StaticHandler) SetupRoutes(r chi.Router) {
+	r.Get("/static/*", h.GetFile())
+}
+
+func (h *StaticHandler) GetFile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "*")
+		if !fs.ValidPath(name) {
+			http.NotFound(w, r)
+			return
+		}
+
+		f, err := h.fsys.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+
+		stat, err := f.Stat()
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if stat.IsDir() {
+			http.NotFound(w, r)
+			return
+		}
+
+		content, err := io.ReadAll(f)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		sum := sha256.Sum256(content)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+		w.Header().Set("Cache-Control", "public, max-age="+staticMaxAge)
+		http.ServeContent(w, r, stat.Name(), stat.ModTime(), bytes.NewReader(content))
+	}
+}
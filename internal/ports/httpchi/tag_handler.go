@@ -0,0 +1,118 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/tag"
+	"documentation-mini-app/internal/views/htmlview"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type TagUsecase interface {
+	GetTags(ctx context.Context, prefix string) ([]tag.Tag, error)
+	GetTagged(ctx context.Context, t string) (*tag.Tagged, error)
+	Search(ctx context.Context, query string, t string) (*tag.Tagged, error)
+}
+
+type TagHandler struct {
+	uc TagUsecase
+
+	views *htmlview.Registry
+}
+
+func NewTagHandler(uc TagUsecase, views *htmlview.Registry) *TagHandler {
+	return &TagHandler{uc: uc, views: views}
+}
+
+func (h *TagHandler) SetupRoutes(r chi.Router) {
+	r.Get("/tags", h.GetTags())
+	r.Get("/tags/{tag}", h.GetTagged())
+	r.Get("/search", h.Search())
+}
+
+// GetTags renders all tags. Tags starting with prefix are written as JSON if format=json is set,
+// it is used for autocomplete in edit forms.
+func (h *TagHandler) GetTags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tags, err := h.uc.GetTags(r.Context(), r.URL.Query().Get("prefix"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json") {
+			w.Header().Set("Content-Type", "application/json")
+
+			err = json.NewEncoder(w).Encode(tags)
+			if err != nil {
+				log.Println(err)
+			}
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "tags/list_tags", tags)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func (h *TagHandler) GetTagged() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		t := chi.URLParam(r, "tag")
+		if r.URL.RawPath != "" {
+			// chi matches escaped path if it has escaped characters like slash.
+			var err error
+			t, err = url.PathUnescape(t)
+			if err != nil {
+				log.Println(err)
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		tagged, err := h.uc.GetTagged(r.Context(), t)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "tags/tagged", tagged)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// Search renders articles and examples found by query q and filtered by tag.
+func (h *TagHandler) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		t := r.URL.Query().Get("tag")
+
+		found := &tag.Tagged{}
+		if strings.TrimSpace(q) != "" || tag.Normalize(t) != "" {
+			var err error
+			found, err = h.uc.Search(r.Context(), q, t)
+			if err != nil {
+				log.Println(err)
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		err := h.views.Render(r.Context(), w, "tags/tagged", found)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/translation"
+	"documentation-mini-app/internal/views/htmlview"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type TranslationUsecase interface {
+	GetTranslation(ctx context.Context, artID int, locale string) (*article.Article, *translation.Translation, error)
+	SaveTranslation(ctx context.Context, t *translation.Translation) error
+	PublishTranslation(ctx context.Context, artID int, locale string) error
+	DeleteTranslation(ctx context.Context, artID int, locale string) error
+}
+
+// exampleField is form field with translated description of example.
+const exampleField = "example_"
+
+// translationPage is the data of translation form template. Translation is nil if article is not translated yet.
+type translationPage struct {
+	*article.Article
+	Locale      string
+	Translation *translation.Translation
+}
+
+// ExampleDescription returns translated description of example.
+func (p translationPage) ExampleDescription(exaID int) string {
+	if p.Translation == nil {
+		return ""
+	}
+
+	return p.Translation.Examples[exaID].Description
+}
+
+type TranslationHandler struct {
+	uc TranslationUsecase
+
+	views *htmlview.Registry
+}
+
+func NewTranslationHandler(uc TranslationUsecase, views *htmlview.Registry) *TranslationHandler {
+	return &TranslationHandler{uc: uc, views: views}
+}
+
+func (h *TranslationHandler) SetupRoutes(r chi.Router) {
+	r.Route("/articles/{articleID}/translations/{locale}", func(r chi.Router) {
+		r.Use(RequireUser)
+
+		r.Get("/", h.GetTranslation())
+		r.Post("/", h.SaveTranslation())
+		r.Post("/publish", h.PublishTranslation())
+		r.Post("/delete", h.DeleteTranslation())
+	})
+}
+
+// translationParams returns article id and locale of translation from URL. Locale must be one of UI locales,
+// otherwise readers can't switch to it.
+func (h *TranslationHandler) translationParams(r *http.Request) (int, string, error) {
+	artID, err := strconv.Atoi(chi.URLParam(r, "articleID"))
+	if err != nil {
+		return 0, "", err
+	}
+
+	locale := chi.URLParam(r, "locale")
+	if !translation.ValidLocale(locale) || !h.views.Supported(locale) {
+		return 0, "", translation.ErrLocale
+	}
+
+	return artID, locale, nil
+}
+
+// writeTranslationError answers with status matching error of translation usecase.
+func writeTranslationError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, translation.ErrLocale), errors.Is(err, translation.ErrNotFound),
+		errors.Is(err, article.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, translation.ErrSelfPublish):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, translation.ErrEmptyName), errors.Is(err, strconv.ErrSyntax),
+		errors.Is(err, strconv.ErrRange):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Println(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetTranslation renders form with source content of article and its translation to locale.
+func (h *TranslationHandler) GetTranslation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		artID, locale, err := h.translationParams(r)
+		if err != nil {
+			writeTranslationError(w, err)
+			return
+		}
+
+		art, t, err := h.uc.GetTranslation(r.Context(), artID, locale)
+		if err != nil {
+			writeTranslationError(w, err)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "translations/edit_translation", translationPage{
+			Article:     art,
+			Locale:      locale,
+			Translation: t,
+		})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func (h *TranslationHandler) SaveTranslation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		artID, locale, err := h.translationParams(r)
+		if err != nil {
+			writeTranslationError(w, err)
+			return
+		}
+
+		q, err := parseForm(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		t := translation.Translation{
+			ArticleID:   artID,
+			Locale:      locale,
+			Name:        q.Get("name"),
+			Description: q.Get("description"),
+			Examples:    make(map[int]translation.Example),
+		}
+
+		for field := range q {
+			idText, ok := strings.CutPrefix(field, exampleField)
+			if !ok {
+				continue
+			}
+
+			id, err := strconv.Atoi(idText)
+			if err != nil {
+				continue
+			}
+			t.Examples[id] = translation.Example{ExampleID: id, Description: q.Get(field)}
+		}
+
+		err = h.uc.SaveTranslation(r.Context(), &t)
+		if err != nil {
+			writeTranslationError(w, err)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("/articles/%v?lang=%v", artID, locale), http.StatusSeeOther)
+	}
+}
+
+func (h *TranslationHandler) PublishTranslation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		artID, locale, err := h.translationParams(r)
+		if err != nil {
+			writeTranslationError(w, err)
+			return
+		}
+
+		err = h.uc.PublishTranslation(r.Context(), artID, locale)
+		if err != nil {
+			writeTranslationError(w, err)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("/articles/%v?lang=%v", artID, locale), http.StatusSeeOther)
+	}
+}
+
+func (h *TranslationHandler) DeleteTranslation() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		artID, locale, err := h.translationParams(r)
+		if err != nil {
+			writeTranslationError(w, err)
+			return
+		}
+
+		err = h.uc.DeleteTranslation(r.Context(), artID, locale)
+		if err != nil {
+			writeTranslationError(w, err)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("/articles/%v", artID), http.StatusSeeOther)
+	}
+}
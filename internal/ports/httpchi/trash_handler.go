@@ -0,0 +1,101 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/trash"
+	"documentation-mini-app/internal/views/htmlview"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type TrashUsecase interface {
+	GetTrash(ctx context.Context) ([]trash.Item, error)
+	GetRetention() time.Duration
+	Restore(ctx context.Context, entityType string, id int) error
+	Purge(ctx context.Context, entityType string, id int) error
+}
+
+// trashPage is the data of trash template.
+type trashPage struct {
+	Items     []trash.Item
+	Retention time.Duration
+}
+
+// RetentionDays returns retention period in days.
+func (p trashPage) RetentionDays() int {
+	return int(p.Retention.Hours() / 24)
+}
+
+type TrashHandler struct {
+	uc TrashUsecase
+
+	views *htmlview.Registry
+}
+
+func NewTrashHandler(uc TrashUsecase, views *htmlview.Registry) *TrashHandler {
+	return &TrashHandler{uc: uc, views: views}
+}
+
+func (h *TrashHandler) SetupRoutes(r chi.Router) {
+	r.Route("/trash", func(r chi.Router) {
+		r.Use(RequireUser)
+
+		r.Get("/", h.GetTrash())
+		r.Post("/{entityType}/{id}/restore", h.Restore())
+		r.Post("/{entityType}/{id}/purge", h.Purge())
+	})
+}
+
+func (h *TrashHandler) GetTrash() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		items, err := h.uc.GetTrash(r.Context())
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "trash/trash", trashPage{Items: items, Retention: h.uc.GetRetention()})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func (h *TrashHandler) Restore() http.HandlerFunc {
+	return h.apply(h.uc.Restore)
+}
+
+func (h *TrashHandler) Purge() http.HandlerFunc {
+	return h.apply(h.uc.Purge)
+}
+
+// apply calls action for item from URL and redirects back to trash.
+func (h *TrashHandler) apply(action func(ctx context.Context, entityType string, id int) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = action(r.Context(), chi.URLParam(r, "entityType"), id)
+		if errors.Is(err, trash.ErrNotInTrash) || errors.Is(err, trash.ErrUnknownEntity) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/trash", http.StatusSeeOther)
+	}
+}
@@ -0,0 +1,219 @@
+package httpchi
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/event"
+	"documentation-mini-app/internal/domain/webhook"
+	"documentation-mini-app/internal/views/htmlview"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type WebhookUsecase interface {
+	GetWebhooks(ctx context.Context, docID int) ([]webhook.Webhook, error)
+	GetWebhook(ctx context.Context, id int) (*webhook.Webhook, []webhook.Delivery, error)
+	CreateWebhook(ctx context.Context, w *webhook.Webhook) error
+	DeleteWebhook(ctx context.Context, id int) error
+	RetryDelivery(ctx context.Context, webhookID int, deliveryID int64) error
+}
+
+// webhooksPage is the data of webhooks list template.
+type webhooksPage struct {
+	DocID    int
+	Webhooks []webhook.Webhook
+	Events   []string
+}
+
+// webhookPage is the data of webhook template with delivery log.
+type webhookPage struct {
+	*webhook.Webhook
+	Deliveries []webhook.Delivery
+}
+
+type WebhookHandler struct {
+	uc WebhookUsecase
+
+	views *htmlview.Registry
+}
+
+func NewWebhookHandler(uc WebhookUsecase, views *htmlview.Registry) *WebhookHandler {
+	return &WebhookHandler{uc: uc, views: views}
+}
+
+func (h *WebhookHandler) SetupRoutes(r chi.Router) {
+	r.Group(func(r chi.Router) {
+		r.Use(RequireUser)
+
+		r.Get("/documentations/{docID}/webhooks", h.GetWebhooks())
+		r.Post("/documentations/{docID}/webhooks", h.CreateWebhook())
+
+		r.Route("/webhooks/{webhookID}", func(r chi.Router) {
+			r.Get("/", h.GetWebhook())
+			r.Post("/delete", h.DeleteWebhook())
+			r.Post("/deliveries/{deliveryID}/retry", h.RetryDelivery())
+		})
+	})
+}
+
+func (h *WebhookHandler) GetWebhooks() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docID, err := strconv.Atoi(chi.URLParam(r, "docID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		hooks, err := h.uc.GetWebhooks(r.Context(), docID)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "webhooks/list_webhooks", webhooksPage{
+			DocID:    docID,
+			Webhooks: hooks,
+			Events:   event.Types,
+		})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		docID, err := strconv.Atoi(chi.URLParam(r, "docID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		q, err := parseForm(r)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		hook := webhook.Webhook{
+			DocID:  docID,
+			URL:    q.Get("url"),
+			Secret: q.Get("secret"),
+			Events: q["events"],
+		}
+
+		err = h.uc.CreateWebhook(r.Context(), &hook)
+		if errors.Is(err, webhook.ErrInvalidURL) || errors.Is(err, webhook.ErrUnknownEvent) ||
+			errors.Is(err, webhook.ErrNoEvents) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("/webhooks/%d", hook.ID), http.StatusSeeOther)
+	}
+}
+
+// GetWebhook renders webhook with its delivery log.
+func (h *WebhookHandler) GetWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "webhookID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		hook, deliveries, err := h.uc.GetWebhook(r.Context(), id)
+		if errors.Is(err, webhook.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = h.views.Render(r.Context(), w, "webhooks/get_webhook", webhookPage{Webhook: hook, Deliveries: deliveries})
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+func (h *WebhookHandler) DeleteWebhook() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "webhookID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		hook, _, err := h.uc.GetWebhook(r.Context(), id)
+		if errors.Is(err, webhook.ErrNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		err = h.uc.DeleteWebhook(r.Context(), id)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("/documentations/%d/webhooks", hook.DocID), http.StatusSeeOther)
+	}
+}
+
+// RetryDelivery queues failed delivery again and redirects back to delivery log.
+func (h *WebhookHandler) RetryDelivery() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := strconv.Atoi(chi.URLParam(r, "webhookID"))
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		deliveryID, err := strconv.ParseInt(chi.URLParam(r, "deliveryID"), 10, 64)
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = h.uc.RetryDelivery(r.Context(), id, deliveryID)
+		if errors.Is(err, webhook.ErrNotRetryable) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		if err != nil {
+			log.Println(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, fmt.Sprintf("/webhooks/%d", id), http.StatusSeeOther)
+	}
+}
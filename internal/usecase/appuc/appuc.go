@@ -6,6 +6,8 @@ import (
 	"documentation-mini-app/internal/domain/article"
 	"documentation-mini-app/internal/domain/crossed"
 	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/tag"
+	"documentation-mini-app/internal/domain/user"
 )
 
 type AppUC struct {
@@ -25,45 +27,59 @@ func (uc *AppUC) GetDocByID(ctx context.Context, id int) (*doc.Documentation, er
 	return d, nil
 }
 
+// GetAllDoc returns all documentations. Readers get only published articles.
 func (uc *AppUC) GetAllDoc(ctx context.Context) ([]*doc.Documentation, error) {
 	docs, err := uc.Store.Doc().GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	if !user.IsEditor(ctx) {
+		for _, d := range docs {
+			d.Articles = article.FilterPublished(d.Articles)
+		}
+	}
+
 	return docs, nil
 }
 
+// GetCrossed returns matrix of articles included into documentations sorted by names.
+// Readers get only published articles.
 func (uc *AppUC) GetCrossed(ctx context.Context) (*crossed.Crossed, error) {
 	docs, err := uc.GetAllDoc(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	articleNames, err := uc.Store.Article().GetAllNames(ctx)
+	arts, err := uc.Store.Article().GetAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	cr := make(map[string]map[string]int)
-	for _, d := range docs {
-		if cr[d.Name] == nil {
-			cr[d.Name] = make(map[string]int)
-		}
+	if !user.IsEditor(ctx) {
+		arts = article.FilterPublished(arts)
+	}
 
-		for _, aName := range articleNames {
-			cr[d.Name][aName] = 0
-		}
+	crsd := crossed.Crossed{
+		Docs:     make([]crossed.Entry, 0, len(docs)),
+		Articles: make([]crossed.Entry, 0, len(arts)),
+		Map:      make(map[int]map[int]int, len(docs)),
+	}
+
+	for _, a := range arts {
+		crsd.Articles = append(crsd.Articles, crossed.Entry{ID: a.ID, Name: a.Name})
+	}
 
+	for _, d := range docs {
+		crsd.Docs = append(crsd.Docs, crossed.Entry{ID: d.ID, Name: d.Name})
+
+		crsd.Map[d.ID] = make(map[int]int, len(d.Articles))
 		for _, a := range d.Articles {
-			cr[d.Name][a.Name]++
+			crsd.Map[d.ID][a.ID]++
 		}
 	}
 
-	crsd := crossed.Crossed{
-		Map:          cr,
-		ArticleNames: articleNames,
-	}
+	crsd = crsd.Apply(crossed.Filter{})
 
 	return &crsd, nil
 }
@@ -74,5 +90,56 @@ func (uc *AppUC) GetArticlesWithoutDoc(ctx context.Context) ([]article.Article,
 		return nil, err
 	}
 
+	if !user.IsEditor(ctx) {
+		arts = article.FilterPublished(arts)
+	}
+
 	return arts, nil
 }
+
+// FilterByTag leaves in documentations only articles marked by tag.
+func (uc *AppUC) FilterByTag(ctx context.Context, docs []*doc.Documentation, t string) error {
+	ids, err := uc.Store.Article().GetIDsByTag(ctx, tag.Normalize(t))
+	if err != nil {
+		return err
+	}
+
+	for _, d := range docs {
+		d.Articles = article.FilterByIDs(d.Articles, ids)
+	}
+
+	return nil
+}
+
+// FillSlugs makes slugs for documentations and articles created before slugs were introduced.
+func (uc *AppUC) FillSlugs(ctx context.Context) error {
+	docs, err := uc.Store.Doc().GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range docs {
+		if d.Slug == "" {
+			err = uc.Store.Doc().SetSlug(ctx, d.ID, d.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	arts, err := uc.Store.Article().GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range arts {
+		if a.Slug == "" {
+			err = uc.Store.Article().SetSlug(ctx, a.ID, a.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,15 @@ import (
 	"context"
 	"documentation-mini-app/internal/adapters/pgstore"
 	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/comment"
+	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/event"
+	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/review"
+	"documentation-mini-app/internal/domain/translation"
+	"documentation-mini-app/internal/domain/user"
+	"errors"
 )
 
 type ArticleUC struct {
@@ -14,34 +23,329 @@ func New(store *pgstore.Store) *ArticleUC {
 	return &ArticleUC{Store: store}
 }
 
+// GetArticleByID returns article. Readers get only published article with published examples.
 func (uc *ArticleUC) GetArticleByID(ctx context.Context, id int) (*article.Article, error) {
 	art, err := uc.Store.Article().GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	if !user.IsEditor(ctx) {
+		if !art.Published {
+			return nil, article.ErrNotFound
+		}
+
+		art.Examples = example.FilterPublished(art.Examples)
+		art.Backlinks = article.FilterPublished(art.Backlinks)
+	}
+
+	return art, nil
+}
+
+// GetArticleBySlug returns article that has slug artSlug now or had it before renaming, and current slug of
+// documentation in which article should be shown. It is documentation with slug docSlug if it contains article,
+// otherwise the first documentation of article. Documentation slug is empty if article belongs to no documentation.
+func (uc *ArticleUC) GetArticleBySlug(ctx context.Context, docSlug string, artSlug string,
+) (*article.Article, string, error) {
+	artID, _, err := uc.Store.Article().GetIDBySlug(ctx, artSlug)
+	if err != nil {
+		return nil, "", err
+	}
+
+	art, err := uc.GetArticleByID(ctx, artID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	docSlugs, err := uc.Store.Article().GetDocSlugs(ctx, artID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if len(docSlugs) == 0 {
+		return art, "", nil
+	}
+
+	_, curDocSlug, err := uc.Store.Doc().GetIDBySlug(ctx, docSlug)
+	if err != nil && !errors.Is(err, doc.ErrNotFound) {
+		return nil, "", err
+	}
+
+	for _, s := range docSlugs {
+		if s == curDocSlug {
+			return art, s, nil
+		}
+	}
+
+	return art, docSlugs[0], nil
+}
+
+// GetArticleTranslation returns translation of article to locale and locales article can be read in,
+// source locale first. Translation is nil for source locale and for locale article is not translated to.
+// Unpublished translations are returned only to editors.
+func (uc *ArticleUC) GetArticleTranslation(ctx context.Context, artID int, locale string,
+) (*translation.Translation, []string, error) {
+	editor := user.IsEditor(ctx)
+
+	locales, err := uc.Store.Translation().GetLocales(ctx, artID, editor)
+	if err != nil {
+		return nil, nil, err
+	}
+	locales = append([]string{translation.Source}, locales...)
+
+	if !translation.ValidLocale(locale) {
+		return nil, locales, nil
+	}
+
+	t, err := uc.Store.Translation().Get(ctx, artID, locale)
+	if errors.Is(err, translation.ErrNotFound) || (err == nil && !t.Published && !editor) {
+		return nil, locales, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t, locales, nil
+}
+
+// GetTextLinks returns references in text resolved to articles, e.g. in translated description of article.
+func (uc *ArticleUC) GetTextLinks(ctx context.Context, text string) ([]article.Link, error) {
+	return uc.Store.Article().ResolveLinks(ctx, article.ParseLinks(text))
+}
+
+// GetArticleDocSlug returns slug of the first documentation of article or empty string if there is none.
+func (uc *ArticleUC) GetArticleDocSlug(ctx context.Context, artID int) (string, error) {
+	docSlugs, err := uc.Store.Article().GetDocSlugs(ctx, artID)
+	if err != nil || len(docSlugs) == 0 {
+		return "", err
+	}
+
+	return docSlugs[0], nil
+}
+
+// GetArticleForEdit returns article with content of open revision of current user, if there is one.
+func (uc *ArticleUC) GetArticleForEdit(ctx context.Context, id int) (*article.Article, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, user.ErrAnonymous
+	}
+
+	art, err := uc.Store.Article().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := uc.Store.Revision().GetOpen(ctx, review.EntityArticle, id, u.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if rev != nil {
+		art.Name = rev.Article.Name
+		art.Description = rev.Article.Description
+		art.Tags = rev.Article.Tags
+		if rev.Article.Version != 0 {
+			art.Version = rev.Article.Version
+		}
+	}
+
 	return art, nil
 }
 
-func (uc *ArticleUC) CreateArticle(ctx context.Context, art *article.Article) error {
-	err := uc.Store.Article().Create(ctx, art)
-	return err
+// GetArticleRevisions returns not published revisions of article. Readers get no revisions.
+func (uc *ArticleUC) GetArticleRevisions(ctx context.Context, id int) ([]review.Revision, error) {
+	if !user.IsEditor(ctx) {
+		return nil, nil
+	}
+
+	return uc.Store.Revision().GetOpenByEntity(ctx, review.EntityArticle, id)
+}
+
+// GetArticleThreads returns discussion threads of article and its examples. Readers get no threads.
+func (uc *ArticleUC) GetArticleThreads(ctx context.Context, id int) ([]comment.Thread, error) {
+	if !user.IsEditor(ctx) {
+		return nil, nil
+	}
+
+	return uc.Store.Comment().GetByArticleID(ctx, id)
+}
+
+// CreateArticle creates unpublished article in documentation docID and draft revision with its content.
+// It returns doc.ErrNotFound if there is no such documentation. Article with zero docID belongs to no
+// documentation.
+func (uc *ArticleUC) CreateArticle(ctx context.Context, art *article.Article, docID int) error {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return user.ErrAnonymous
+	}
+
+	art.Published = false
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		if docID != 0 {
+			_, err := tx.Doc().GetByID(ctx, docID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := tx.Article().Create(ctx, art)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Article().SetLinks(ctx, art.ID, article.ParseLinks(art.Description))
+		if err != nil {
+			return err
+		}
+
+		err = tx.Article().SetTags(ctx, art.ID, art.Tags)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Article().SetSlug(ctx, art.ID, art.Name)
+		if err != nil {
+			return err
+		}
+
+		rev := review.Revision{
+			EntityType: review.EntityArticle,
+			EntityID:   art.ID,
+			Article:    &article.Article{ID: art.ID, Name: art.Name, Description: art.Description, Tags: art.Tags},
+			Status:     review.StatusDraft,
+			Author:     u.Name,
+		}
+
+		err = tx.Revision().Create(ctx, &rev)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Audit().Record(ctx, audit.ActionCreate, audit.EntityArticle, art.ID, nil, art)
+		if err != nil || docID == 0 {
+			return err
+		}
+
+		return addToDoc(ctx, tx, art.ID, docID)
+	})
 }
 
 func (uc *ArticleUC) AddArticleToDoc(ctx context.Context, artID int, docID int) error {
-	if docID != 0 {
-		err := uc.Store.Article().AddToDoc(ctx, artID, docID)
+	if docID == 0 {
+		return nil
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		return addToDoc(ctx, tx, artID, docID)
+	})
+}
+
+// addToDoc adds article to documentation in transaction tx and records it.
+func addToDoc(ctx context.Context, tx *pgstore.Store, artID int, docID int) error {
+	err := tx.Article().AddToDoc(ctx, artID, docID)
+	if err != nil {
 		return err
 	}
-	return nil
+
+	err = tx.Audit().Record(ctx, audit.ActionLink, audit.EntityArticle, artID, nil,
+		map[string]int{"documentation_id": docID})
+	if err != nil {
+		return err
+	}
+
+	return tx.Event().Emit(ctx, event.ArticleAdded, artID)
 }
 
-func (uc *ArticleUC) UpdateArticle(ctx context.Context, art *article.Article) error {
-	err := uc.Store.Article().Update(ctx, art)
-	return err
+// SaveArticleDraft saves new content of article into open revision of current user without publishing it.
+// art.Version must be the version of article the editor started from, otherwise article.ErrConflict is returned.
+func (uc *ArticleUC) SaveArticleDraft(ctx context.Context, art *article.Article) (*review.Revision, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, user.ErrAnonymous
+	}
+
+	var rev *review.Revision
+	err := uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		version, err := tx.Article().LockVersion(ctx, art.ID)
+		if err != nil {
+			return err
+		}
+
+		if art.Version != version {
+			return article.ErrConflict
+		}
+
+		content := &article.Article{
+			ID:          art.ID,
+			Name:        art.Name,
+			Description: art.Description,
+			Tags:        art.Tags,
+			Version:     art.Version,
+		}
+
+		rev, err = tx.Revision().GetOpen(ctx, review.EntityArticle, art.ID, u.Name)
+		if err != nil {
+			return err
+		}
+
+		if rev == nil {
+			rev = &review.Revision{
+				EntityType: review.EntityArticle,
+				EntityID:   art.ID,
+				Article:    content,
+				Status:     review.StatusDraft,
+				Author:     u.Name,
+			}
+
+			err = tx.Revision().Create(ctx, rev)
+			if err != nil {
+				return err
+			}
+
+			return tx.Audit().Record(ctx, audit.ActionCreate, audit.EntityRevision, rev.ID, nil, rev)
+		}
+
+		before := *rev
+
+		err = rev.Edit(u.Name)
+		if err != nil {
+			return err
+		}
+		rev.Article = content
+
+		err = tx.Revision().Update(ctx, rev)
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, audit.ActionUpdate, audit.EntityRevision, rev.ID, before, rev)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rev, nil
 }
 
+// DeleteArticle moves article to trash.
 func (uc *ArticleUC) DeleteArticle(ctx context.Context, artID int) error {
-	err := uc.Store.Article().Delete(ctx, artID)
-	return err
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		before, err := tx.Article().GetByID(ctx, artID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Article().Delete(ctx, artID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Audit().Record(ctx, audit.ActionDelete, audit.EntityArticle, artID, before, nil)
+		if err != nil {
+			return err
+		}
+
+		return tx.Event().Emit(ctx, event.ArticleDeleted, artID)
+	})
 }
@@ -0,0 +1,25 @@
+package audituc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/user"
+)
+
+type AuditUC struct {
+	Store *pgstore.Store
+}
+
+func New(store *pgstore.Store) *AuditUC {
+	return &AuditUC{Store: store}
+}
+
+// GetEntries returns audit entries matched by filter, recent first.
+func (uc *AuditUC) GetEntries(ctx context.Context, f audit.Filter) ([]audit.Entry, error) {
+	if !user.IsEditor(ctx) {
+		return nil, user.ErrAnonymous
+	}
+
+	return uc.Store.Audit().Find(ctx, f)
+}
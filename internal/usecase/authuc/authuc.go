@@ -0,0 +1,101 @@
+package authuc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"documentation-mini-app/internal/domain/user"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AuthUC checks passwords of editors and issues signed sessions, so users can't name themselves.
+type AuthUC struct {
+	// users maps user names to bcrypt hashes of their passwords.
+	users map[string]string
+	key   []byte
+	// dummy is compared with password of unknown user, so response time doesn't reveal user names.
+	dummy []byte
+	now   func() time.Time
+}
+
+// New returns AuthUC with users and key signing sessions. Random key is generated if key is empty, then sessions
+// don't survive restarts.
+func New(users map[string]string, key []byte) (*AuthUC, error) {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, err := rand.Read(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dummy, err := bcrypt.GenerateFromPassword(key, bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthUC{users: users, key: key, dummy: dummy, now: time.Now}, nil
+}
+
+// Login checks password of user and returns session of the user. It returns user.ErrInvalidCredentials if user
+// is unknown or password doesn't match.
+func (uc *AuthUC) Login(_ context.Context, name, password string) (string, error) {
+	hash, ok := uc.users[name]
+	if !ok {
+		_ = bcrypt.CompareHashAndPassword(uc.dummy, []byte(password))
+		return "", user.ErrInvalidCredentials
+	}
+
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if err != nil {
+		return "", user.ErrInvalidCredentials
+	}
+
+	expires := strconv.FormatInt(uc.now().Add(user.SessionTTL).Unix(), 10)
+	payload := base64.RawURLEncoding.EncodeToString([]byte(name)) + "." + expires
+
+	return payload + "." + uc.sign(payload), nil
+}
+
+// User returns user of session. It returns user.ErrInvalidSession if session is forged, expired or its user was
+// removed from configuration.
+func (uc *AuthUC) User(_ context.Context, session string) (*user.User, error) {
+	i := strings.LastIndexByte(session, '.')
+	if i < 0 || !hmac.Equal([]byte(session[i+1:]), []byte(uc.sign(session[:i]))) {
+		return nil, user.ErrInvalidSession
+	}
+
+	encName, expires, ok := strings.Cut(session[:i], ".")
+	if !ok {
+		return nil, user.ErrInvalidSession
+	}
+
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || uc.now().Unix() >= exp {
+		return nil, user.ErrInvalidSession
+	}
+
+	name, err := base64.RawURLEncoding.DecodeString(encName)
+	if err != nil {
+		return nil, user.ErrInvalidSession
+	}
+
+	if _, ok := uc.users[string(name)]; !ok {
+		return nil, user.ErrInvalidSession
+	}
+
+	return &user.User{Name: string(name)}, nil
+}
+
+func (uc *AuthUC) sign(payload string) string {
+	mac := hmac.New(sha256.New, uc.key)
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
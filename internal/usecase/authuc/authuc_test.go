@@ -0,0 +1,89 @@
+package authuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/domain/user"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func testUC(t *testing.T) *AuthUC {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	uc, err := New(map[string]string{"alice": string(hash)}, []byte("key"))
+	require.NoError(t, err)
+
+	return uc
+}
+
+func TestLogin(t *testing.T) {
+	ctx := context.Background()
+	uc := testUC(t)
+
+	_, err := uc.Login(ctx, "alice", "wrong")
+	assert.ErrorIs(t, err, user.ErrInvalidCredentials)
+	_, err = uc.Login(ctx, "bob", "secret")
+	assert.ErrorIs(t, err, user.ErrInvalidCredentials)
+
+	session, err := uc.Login(ctx, "alice", "secret")
+	require.NoError(t, err)
+
+	u, err := uc.User(ctx, session)
+	require.NoError(t, err)
+	assert.Equal(t, &user.User{Name: "alice"}, u)
+}
+
+func TestUser_Invalid(t *testing.T) {
+	ctx := context.Background()
+	uc := testUC(t)
+
+	session, err := uc.Login(ctx, "alice", "secret")
+	require.NoError(t, err)
+
+	parts := strings.Split(session, ".")
+	require.Len(t, parts, 3)
+
+	forged := []string{
+		"",
+		"alice",
+		"YWxpY2U.99999999999.",
+		// Name of other user with signature of alice.
+		"Ym9i." + parts[1] + "." + parts[2],
+		// Prolonged session.
+		parts[0] + ".99999999999." + parts[2],
+	}
+	for _, s := range forged {
+		_, err = uc.User(ctx, s)
+		assert.ErrorIs(t, err, user.ErrInvalidSession, s)
+	}
+
+	other, err := New(uc.users, []byte("other key"))
+	require.NoError(t, err)
+	_, err = other.User(ctx, session)
+	assert.ErrorIs(t, err, user.ErrInvalidSession)
+
+	uc.now = func() time.Time { return time.Now().Add(user.SessionTTL + time.Minute) }
+	_, err = uc.User(ctx, session)
+	assert.ErrorIs(t, err, user.ErrInvalidSession)
+
+	uc.now = time.Now
+	delete(uc.users, "alice")
+	_, err = uc.User(ctx, session)
+	assert.ErrorIs(t, err, user.ErrInvalidSession)
+}
+
+func TestNew_RandomKey(t *testing.T) {
+	a, err := New(nil, nil)
+	require.NoError(t, err)
+	b, err := New(nil, nil)
+	require.NoError(t, err)
+
+	assert.Len(t, a.key, 32)
+	assert.NotEqual(t, a.key, b.key)
+}
@@ -0,0 +1,323 @@
+package checkuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/check"
+	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/example"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// internalURLRe matches relative and absolute links to pages of the site.
+var internalURLRe = regexp.MustCompile(
+	`(?:^|[\s("'=])((?:https?://[^/\s"'<>()]+)?)/(documentations|articles|examples)/(\d+)`)
+
+// slugURLRe matches relative and absolute links to articles by slugs of documentation and article.
+var slugURLRe = regexp.MustCompile(
+	`(?:^|[\s("'=])((?:https?://[^/\s"'<>()]+)?)/docs/([^/\s"'<>()?#]+)/([^/\s"'<>()?#]+)`)
+
+type CheckUC struct {
+	Store *pgstore.Store
+
+	// host of the site. Absolute links to other hosts are not checked.
+	host string
+}
+
+func New(store *pgstore.Store, baseURL string) *CheckUC {
+	uc := &CheckUC{Store: store}
+
+	u, err := url.Parse(baseURL)
+	if err == nil {
+		uc.host = u.Host
+	}
+
+	return uc
+}
+
+// entities contains everything needed for checks.
+type entities struct {
+	docs     []*doc.Documentation
+	articles []article.Article
+	examples []example.Example
+
+	ids map[string]map[int]bool
+	// slugs are ids of articles by slugs used in links, slug that leads to no article is missing.
+	slugs map[string]int
+}
+
+// Check finds orphans, duplicates, empty descriptions, examples without output and broken internal links.
+func (uc *CheckUC) Check(ctx context.Context) (*check.Report, error) {
+	e, err := uc.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &check.Report{Issues: make([]check.Issue, 0), Counts: make(map[string]int)}
+
+	err = uc.checkOrphans(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.checkContent(report, e)
+
+	return report, nil
+}
+
+// checkContent finds duplicates, empty descriptions, examples without output and broken internal links.
+func (uc *CheckUC) checkContent(report *check.Report, e *entities) {
+	checkDuplicates(report, e.articles)
+
+	for _, a := range e.articles {
+		if strings.TrimSpace(a.Description) == "" {
+			report.Add(articleIssue(check.KindEmptyDescription, a, "article has empty description"))
+		}
+
+		uc.checkURLs(report, e, check.EntityArticle, a.ID, a.Name, a.Description)
+		checkRefs(report, e, a)
+	}
+
+	for _, exa := range e.examples {
+		if strings.TrimSpace(exa.Description) == "" {
+			report.Add(exampleIssue(check.KindEmptyDescription, exa, "example has empty description"))
+		}
+
+		if strings.TrimSpace(exa.Output) == "" {
+			report.Add(exampleIssue(check.KindExampleNoOutput, exa, "example has no output"))
+		}
+
+		uc.checkURLs(report, e, check.EntityExample, exa.ID, exa.Name, exa.Description)
+	}
+}
+
+func (uc *CheckUC) load(ctx context.Context) (*entities, error) {
+	docs, err := uc.Store.Doc().GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	arts, err := uc.Store.Article().GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exas, err := uc.Store.Example().GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	e := newEntities(docs, arts, exas)
+
+	err = uc.resolveSlugs(ctx, e)
+	if err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// resolveSlugs finds articles by slugs used in links of descriptions. Article is found by its previous slug
+// too, as such link is redirected to the article.
+func (uc *CheckUC) resolveSlugs(ctx context.Context, e *entities) error {
+	texts := make([]string, 0, len(e.articles)+len(e.examples))
+	for _, a := range e.articles {
+		texts = append(texts, a.Description)
+	}
+	for _, exa := range e.examples {
+		texts = append(texts, exa.Description)
+	}
+
+	checked := make(map[string]bool)
+	for _, text := range texts {
+		for _, m := range slugURLRe.FindAllStringSubmatch(text, -1) {
+			if checked[m[3]] {
+				continue
+			}
+			checked[m[3]] = true
+
+			id, _, err := uc.Store.Article().GetIDBySlug(ctx, m[3])
+			if errors.Is(err, article.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			e.slugs[m[3]] = id
+		}
+	}
+
+	return nil
+}
+
+func newEntities(docs []*doc.Documentation, arts []article.Article, exas []example.Example) *entities {
+	e := entities{
+		docs:     docs,
+		articles: arts,
+		examples: exas,
+		ids: map[string]map[int]bool{
+			"documentations": make(map[int]bool, len(docs)),
+			"articles":       make(map[int]bool, len(arts)),
+			"examples":       make(map[int]bool, len(exas)),
+		},
+		slugs: make(map[string]int),
+	}
+
+	for _, d := range docs {
+		e.ids["documentations"][d.ID] = true
+	}
+	for _, a := range arts {
+		e.ids["articles"][a.ID] = true
+	}
+	for _, exa := range exas {
+		e.ids["examples"][exa.ID] = true
+	}
+
+	return &e
+}
+
+func (uc *CheckUC) checkOrphans(ctx context.Context, report *check.Report) error {
+	exas, err := uc.Store.Example().GetWithoutArticle(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, exa := range exas {
+		report.Add(exampleIssue(check.KindOrphanExample, exa, "example doesn't belong to any article"))
+	}
+
+	arts, err := uc.Store.Article().GetWithoutDoc(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range arts {
+		report.Add(articleIssue(check.KindArticleWithoutDoc, a, "article doesn't belong to any documentation"))
+	}
+
+	return nil
+}
+
+func checkDuplicates(report *check.Report, arts []article.Article) {
+	byName := make(map[string][]int)
+	for _, a := range arts {
+		name := strings.ToLower(strings.TrimSpace(a.Name))
+		byName[name] = append(byName[name], a.ID)
+	}
+
+	for _, a := range arts {
+		ids := byName[strings.ToLower(strings.TrimSpace(a.Name))]
+		if len(ids) < 2 {
+			continue
+		}
+
+		others := make([]string, 0, len(ids)-1)
+		for _, id := range ids {
+			if id != a.ID {
+				others = append(others, strconv.Itoa(id))
+			}
+		}
+
+		report.Add(articleIssue(check.KindDuplicateArticle, a,
+			fmt.Sprintf("article name is also used by articles %v", strings.Join(others, ", "))))
+	}
+}
+
+// checkURLs reports links to pages of the site that don't exist. Link by slugs is broken if article slug leads
+// to no article, documentation slug doesn't matter as article is redirected to its documentation.
+func (uc *CheckUC) checkURLs(report *check.Report, e *entities, entityType string, id int, name string, text string) {
+	broken := func(link string) {
+		report.Add(check.Issue{
+			Kind:       check.KindBrokenLink,
+			EntityType: entityType,
+			EntityID:   id,
+			EntityName: name,
+			Message:    fmt.Sprintf("link %v leads to missing page", link),
+		})
+	}
+
+	for _, m := range internalURLRe.FindAllStringSubmatch(text, -1) {
+		if !uc.internal(m[1]) {
+			continue
+		}
+
+		targetID, err := strconv.Atoi(m[3])
+		if err != nil || !e.ids[m[2]][targetID] {
+			broken(fmt.Sprintf("%v/%v/%v", m[1], m[2], m[3]))
+		}
+	}
+
+	for _, m := range slugURLRe.FindAllStringSubmatch(text, -1) {
+		if !uc.internal(m[1]) {
+			continue
+		}
+
+		targetID, ok := e.slugs[m[3]]
+		if !ok || !e.ids["articles"][targetID] {
+			broken(fmt.Sprintf("%v/docs/%v/%v", m[1], m[2], m[3]))
+		}
+	}
+}
+
+// internal reports whether link with scheme and host prefix leads to the site. Relative link has empty prefix.
+func (uc *CheckUC) internal(prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+
+	u, err := url.Parse(prefix)
+	return err == nil && u.Host == uc.host
+}
+
+// checkRefs reports [[...]] references that can't be resolved to any published article.
+func checkRefs(report *check.Report, e *entities, a article.Article) {
+	for _, l := range article.ParseLinks(a.Description) {
+		if !resolves(e, l) {
+			report.Add(articleIssue(check.KindBrokenLink, a,
+				fmt.Sprintf("reference [[%v]] leads to missing article", l.Ref())))
+		}
+	}
+}
+
+func resolves(e *entities, l article.Link) bool {
+	if l.DocName == "" {
+		for _, a := range e.articles {
+			if a.Published && a.Name == l.ArticleName {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, d := range e.docs {
+		if d.Name != l.DocName {
+			continue
+		}
+
+		for _, a := range d.Articles {
+			if a.Published && a.Name == l.ArticleName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func articleIssue(kind string, a article.Article, msg string) check.Issue {
+	return check.Issue{Kind: kind, EntityType: check.EntityArticle, EntityID: a.ID, EntityName: a.Name, Message: msg}
+}
+
+func exampleIssue(kind string, exa example.Example, msg string) check.Issue {
+	return check.Issue{
+		Kind: kind, EntityType: check.EntityExample, EntityID: exa.ID, EntityName: exa.Name, Message: msg,
+	}
+}
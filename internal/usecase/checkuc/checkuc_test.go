@@ -0,0 +1,120 @@
+package checkuc
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/check"
+	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/example"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Host(t *testing.T) {
+	assert.Equal(t, "docs.example.com", New(nil, "https://docs.example.com/").host)
+	assert.Equal(t, "", New(nil, "").host)
+}
+
+func TestCheckURLs(t *testing.T) {
+	uc := &CheckUC{host: "docs.example.com"}
+	e := newEntities([]*doc.Documentation{{ID: 1}}, []article.Article{{ID: 2}}, []example.Example{{ID: 3}})
+
+	text := "/documentations/1 and (/articles/2) href=\"/examples/3\" are fine.\n" +
+		"/articles/20 is missing, https://docs.example.com/examples/4 too.\n" +
+		"https://other.example.com/articles/5 isn't checked, neither is path/articles/6 nor /users/7."
+
+	report := &check.Report{}
+	uc.checkURLs(report, e, check.EntityArticle, 10, "a", text)
+
+	require.Len(t, report.Issues, 2)
+	assert.Equal(t, check.Issue{
+		Kind:       check.KindBrokenLink,
+		EntityType: check.EntityArticle,
+		EntityID:   10,
+		EntityName: "a",
+		Message:    "link /articles/20 leads to missing page",
+	}, report.Issues[0])
+	assert.Equal(t, "link https://docs.example.com/examples/4 leads to missing page", report.Issues[1].Message)
+}
+
+func TestCheckURLs_Slugs(t *testing.T) {
+	uc := &CheckUC{host: "docs.example.com"}
+	e := newEntities(nil, []article.Article{{ID: 2}}, nil)
+	e.slugs["maps"] = 2
+	e.slugs["old-maps"] = 2
+	e.slugs["trashed"] = 5
+
+	text := "/docs/go/maps and (https://docs.example.com/docs/rust/old-maps) are fine.\n" +
+		"/docs/go/slices is missing, href=\"/docs/go/trashed\" too.\n" +
+		"https://other.example.com/docs/go/slices isn't checked, neither is /docs/go."
+
+	report := &check.Report{}
+	uc.checkURLs(report, e, check.EntityExample, 10, "a", text)
+
+	require.Len(t, report.Issues, 2)
+	assert.Equal(t, "link /docs/go/slices leads to missing page", report.Issues[0].Message)
+	assert.Equal(t, "link /docs/go/trashed leads to missing page", report.Issues[1].Message)
+}
+
+func TestCheckDuplicates(t *testing.T) {
+	report := &check.Report{}
+	checkDuplicates(report, []article.Article{
+		{ID: 1, Name: "Maps"},
+		{ID: 2, Name: " maps "},
+		{ID: 3, Name: "Slices"},
+		{ID: 4, Name: "MAPS"},
+	})
+
+	require.Len(t, report.Issues, 3)
+	assert.Equal(t, 3, report.Counts[check.KindDuplicateArticle])
+	assert.Equal(t, 1, report.Issues[0].EntityID)
+	assert.Equal(t, "article name is also used by articles 2, 4", report.Issues[0].Message)
+	assert.Equal(t, "article name is also used by articles 1, 2", report.Issues[2].Message)
+}
+
+func TestCheckRefs(t *testing.T) {
+	arts := []article.Article{
+		{ID: 1, Name: "Maps", Published: true}, {ID: 2, Name: "TCP/IP", Published: true}, {ID: 4, Name: "Draft"},
+	}
+	docs := []*doc.Documentation{{ID: 1, Name: "Go", Articles: []article.Article{arts[0], arts[2]}}}
+	e := newEntities(docs, arts, nil)
+
+	assert.True(t, resolves(e, article.Link{ArticleName: "Maps"}))
+	assert.True(t, resolves(e, article.Link{DocName: "Go", ArticleName: "Maps"}))
+	assert.True(t, resolves(e, article.Link{ArticleName: "TCP/IP"}))
+	assert.False(t, resolves(e, article.Link{DocName: "Go", ArticleName: "TCP/IP"}))
+	assert.False(t, resolves(e, article.Link{DocName: "Rust", ArticleName: "Maps"}))
+	assert.False(t, resolves(e, article.Link{ArticleName: "Draft"}))
+	assert.False(t, resolves(e, article.Link{DocName: "Go", ArticleName: "Draft"}))
+
+	a := article.Article{
+		ID: 3, Name: "Intro", Description: `See [[Maps]], [[TCP\/IP]], [[Go/Slices]] and [[Net/TCP\/IP]].`,
+	}
+	report := &check.Report{}
+	checkRefs(report, e, a)
+
+	require.Len(t, report.Issues, 2)
+	assert.Equal(t, "reference [[Go/Slices]] leads to missing article", report.Issues[0].Message)
+	assert.Equal(t, `reference [[Net/TCP\/IP]] leads to missing article`, report.Issues[1].Message)
+}
+
+func TestCheckContent(t *testing.T) {
+	e := newEntities(nil, []article.Article{
+		{ID: 1, Name: "Maps", Description: "Text"},
+		{ID: 2, Name: "Empty", Description: " \n"},
+	}, []example.Example{
+		{ID: 1, Name: "ok", Description: "Text", Output: "1"},
+		{ID: 2, Name: "bare", Description: "See /examples/9"},
+	})
+
+	report := &check.Report{}
+	(&CheckUC{}).checkContent(report, e)
+
+	assert.Equal(t, map[string]int{
+		check.KindEmptyDescription: 1,
+		check.KindExampleNoOutput:  1,
+		check.KindBrokenLink:       1,
+	}, report.Counts)
+	assert.False(t, report.OK())
+}
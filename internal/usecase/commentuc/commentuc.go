@@ -0,0 +1,138 @@
+package commentuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/comment"
+	"documentation-mini-app/internal/domain/user"
+	"strings"
+)
+
+type CommentUC struct {
+	Store *pgstore.Store
+}
+
+func New(store *pgstore.Store) *CommentUC {
+	return &CommentUC{Store: store}
+}
+
+// StartThread creates thread on article or, if exaID is not zero, on its example.
+func (uc *CommentUC) StartThread(ctx context.Context, artID int, exaID int, body string) (*comment.Thread, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, user.ErrAnonymous
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, comment.ErrEmptyBody
+	}
+
+	if exaID != 0 {
+		ok, err := uc.Store.Example().InArticle(ctx, exaID, artID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, comment.ErrAlienExample
+		}
+	}
+
+	t := comment.Thread{
+		ArticleID: artID,
+		ExampleID: exaID,
+		Author:    u.Name,
+	}
+
+	c := comment.Comment{Author: u.Name, Body: body}
+
+	err := uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		err := tx.Comment().CreateThread(ctx, &t, &c)
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, audit.ActionCreate, audit.EntityThread, t.ID, nil, t)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (uc *CommentUC) Reply(ctx context.Context, threadID int, body string) (*comment.Thread, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, user.ErrAnonymous
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, comment.ErrEmptyBody
+	}
+
+	t, err := uc.Store.Comment().GetThread(ctx, threadID)
+	if err != nil {
+		return nil, err
+	}
+
+	c := comment.Comment{ThreadID: threadID, Author: u.Name, Body: body}
+
+	err = uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		err := tx.Comment().AddComment(ctx, &c)
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, audit.ActionCreate, audit.EntityComment, c.ID, nil, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+	t.Comments = append(t.Comments, c)
+
+	return t, nil
+}
+
+// SetResolved resolves or reopens thread.
+func (uc *CommentUC) SetResolved(ctx context.Context, threadID int, resolved bool) (*comment.Thread, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, user.ErrAnonymous
+	}
+
+	by := ""
+	if resolved {
+		by = u.Name
+	}
+
+	action := audit.ActionReopen
+	if resolved {
+		action = audit.ActionResolve
+	}
+
+	err := uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		err := tx.Comment().SetResolved(ctx, threadID, resolved, by)
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, action, audit.EntityThread, threadID, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.Store.Comment().GetThread(ctx, threadID)
+}
+
+// GetOpenDocThreads returns unresolved threads of all articles of documentation.
+func (uc *CommentUC) GetOpenDocThreads(ctx context.Context, docID int) ([]comment.Thread, error) {
+	if !user.IsEditor(ctx) {
+		return nil, user.ErrAnonymous
+	}
+
+	return uc.Store.Comment().GetOpenByDocID(ctx, docID)
+}
@@ -0,0 +1,85 @@
+package commentuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/comment"
+	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/user"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testUC(t *testing.T) *CommentUC {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("need TEST_DATABASE_URL env variable")
+	}
+
+	ctx := context.Background()
+	store, truncate := pgstore.TestStore(ctx, t, dbURL)
+	t.Cleanup(func() {
+		truncate(ctx, "article", "example", "comment_thread", "comment", "audit_log")
+	})
+
+	return New(store)
+}
+
+func as(name string) context.Context {
+	return user.NewContext(context.Background(), &user.User{Name: name})
+}
+
+func TestStartThread_Invalid(t *testing.T) {
+	uc := New(nil)
+
+	_, err := uc.StartThread(context.Background(), 1, 0, "text")
+	assert.ErrorIs(t, err, user.ErrAnonymous)
+	_, err = uc.StartThread(as("alice"), 1, 0, " \n")
+	assert.ErrorIs(t, err, comment.ErrEmptyBody)
+	_, err = uc.Reply(as("alice"), 1, "")
+	assert.ErrorIs(t, err, comment.ErrEmptyBody)
+	_, err = uc.SetResolved(context.Background(), 1, true)
+	assert.ErrorIs(t, err, user.ErrAnonymous)
+}
+
+func TestStartThread(t *testing.T) {
+	uc := testUC(t)
+	ctx := as("alice")
+
+	arts := []*article.Article{{Name: "Maps"}, {Name: "Slices"}}
+	for _, a := range arts {
+		require.NoError(t, uc.Store.Article().Create(ctx, a))
+	}
+	exa := &example.Example{Name: "make"}
+	require.NoError(t, uc.Store.Example().Create(ctx, exa))
+	require.NoError(t, uc.Store.Example().AddToArticle(ctx, exa.ID, arts[0].ID))
+
+	th, err := uc.StartThread(ctx, arts[0].ID, exa.ID, " Why? ")
+	require.NoError(t, err)
+	assert.Equal(t, exa.ID, th.ExampleID)
+
+	_, err = uc.StartThread(ctx, arts[1].ID, exa.ID, "Why?")
+	assert.ErrorIs(t, err, comment.ErrAlienExample)
+	_, err = uc.StartThread(ctx, arts[0].ID, exa.ID+1, "Why?")
+	assert.ErrorIs(t, err, comment.ErrAlienExample)
+
+	th, err = uc.Reply(as("bob"), th.ID, "Because")
+	require.NoError(t, err)
+	require.Len(t, th.Comments, 2)
+	assert.Equal(t, "Why?", th.Comments[0].Body)
+	assert.Equal(t, "bob", th.Comments[1].Author)
+
+	th, err = uc.SetResolved(as("bob"), th.ID, true)
+	require.NoError(t, err)
+	assert.True(t, th.Resolved)
+	assert.Equal(t, "bob", th.ResolvedBy)
+
+	th, err = uc.SetResolved(ctx, th.ID, false)
+	require.NoError(t, err)
+	assert.False(t, th.Resolved)
+	assert.Empty(t, th.ResolvedBy)
+}
@@ -3,7 +3,14 @@ package docuc
 import (
 	"context"
 	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/audit"
 	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/event"
+	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/tag"
+	"documentation-mini-app/internal/domain/user"
+	"strings"
 )
 
 type DocUC struct {
@@ -16,20 +23,304 @@ func New(store *pgstore.Store) *DocUC {
 
 func (uc *DocUC) GetDocByID(ctx context.Context, docID int) (*doc.Documentation, error) {
 	d, err := uc.Store.Doc().GetByID(ctx, docID)
-	return d, err
+	if err != nil {
+		return nil, err
+	}
+
+	d.Versions, err = uc.Store.DocVersion().GetByDocID(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsEditor(ctx) {
+		d.Articles = article.FilterPublished(d.Articles)
+	}
+
+	return d, nil
+}
+
+// FilterByTag leaves in documentation only articles marked by tag.
+func (uc *DocUC) FilterByTag(ctx context.Context, d *doc.Documentation, t string) error {
+	ids, err := uc.Store.Article().GetIDsByTag(ctx, tag.Normalize(t))
+	if err != nil {
+		return err
+	}
+
+	d.Articles = article.FilterByIDs(d.Articles, ids)
+
+	return nil
+}
+
+// GetDocBySlug returns documentation that has slug s now or had it before renaming.
+func (uc *DocUC) GetDocBySlug(ctx context.Context, s string) (*doc.Documentation, error) {
+	docID, _, err := uc.Store.Doc().GetIDBySlug(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.GetDocByID(ctx, docID)
 }
 
 func (uc *DocUC) CreateDoc(ctx context.Context, d *doc.Documentation) error {
-	err := uc.Store.Doc().Create(ctx, d)
-	return err
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		err := tx.Doc().Create(ctx, d)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Doc().SetSlug(ctx, d.ID, d.Name)
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, audit.ActionCreate, audit.EntityDoc, d.ID, nil, d)
+	})
 }
 
 func (uc *DocUC) UpdateDoc(ctx context.Context, d *doc.Documentation) error {
-	err := uc.Store.Doc().Update(ctx, d)
-	return err
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		before, err := tx.Doc().GetByID(ctx, d.ID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Doc().Update(ctx, d)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Doc().SetSlug(ctx, d.ID, d.Name)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Audit().Record(ctx, audit.ActionUpdate, audit.EntityDoc, d.ID, before, d)
+		if err != nil {
+			return err
+		}
+
+		return tx.Event().Emit(ctx, event.DocUpdated, d.ID)
+	})
+}
+
+// GetDeletePlan returns articles and examples affected by deletion of documentation.
+func (uc *DocUC) GetDeletePlan(ctx context.Context, docID int) (*doc.DeletePlan, error) {
+	return deletePlan(ctx, uc.Store, docID)
+}
+
+func deletePlan(ctx context.Context, s *pgstore.Store, docID int) (*doc.DeletePlan, error) {
+	d, err := s.Doc().GetByID(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := doc.DeletePlan{Doc: d, Shared: make([]article.Article, 0)}
+
+	plan.Exclusive, err = s.Article().GetExclusiveByDocID(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.ExclusiveExamples, err = s.Example().GetExclusiveByDocID(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclusive := make(map[int]bool, len(plan.Exclusive))
+	for _, a := range plan.Exclusive {
+		exclusive[a.ID] = true
+	}
+
+	for _, a := range d.Articles {
+		if !exclusive[a.ID] {
+			plan.Shared = append(plan.Shared, a)
+		}
+	}
+
+	return &plan, nil
+}
+
+// DeleteDoc moves documentation to trash. Depending on cascade mode its exclusive articles and their
+// exclusive examples are moved to trash too. Every trashed article and example gets its own audit entry,
+// articles left without documentation are recorded as unlinked from it.
+func (uc *DocUC) DeleteDoc(ctx context.Context, docID int, cascade string) error {
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		plan, err := deletePlan(ctx, tx, docID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Doc().DeleteCascade(ctx, docID, cascade)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Audit().Record(ctx, audit.ActionDelete, audit.EntityDoc, docID, plan,
+			map[string]string{"cascade": cascade})
+		if err != nil {
+			return err
+		}
+
+		for _, c := range cascadeChanges(plan, cascade) {
+			err = tx.Audit().Record(ctx, c.action, c.entityType, c.entityID, c.before,
+				map[string]int{"documentation_id": docID})
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Event().Emit(ctx, event.DocDeleted, docID)
+	})
+}
+
+// cascadeChange is change of article or example made by deletion of its documentation.
+type cascadeChange struct {
+	action     string
+	entityType string
+	entityID   int
+	before     any
 }
 
-func (uc *DocUC) DeleteDoc(ctx context.Context, docID int) error {
-	err := uc.Store.Doc().Delete(ctx, docID)
-	return err
+// cascadeChanges returns articles and examples moved to trash with documentation according to cascade mode
+// and articles unlinked from it. Unlinked articles stay as they are, so they have no previous state.
+func cascadeChanges(plan *doc.DeletePlan, cascade string) []cascadeChange {
+	res := make([]cascadeChange, 0, len(plan.Shared)+len(plan.Exclusive)+len(plan.ExclusiveExamples))
+	for _, a := range plan.Shared {
+		res = append(res, cascadeChange{action: audit.ActionUnlink, entityType: audit.EntityArticle, entityID: a.ID})
+	}
+
+	for _, a := range plan.Exclusive {
+		c := cascadeChange{action: audit.ActionDelete, entityType: audit.EntityArticle, entityID: a.ID, before: a}
+		if cascade == doc.CascadeUnlink {
+			c.action, c.before = audit.ActionUnlink, nil
+		}
+		res = append(res, c)
+	}
+
+	if cascade == doc.CascadeExamples {
+		for _, e := range plan.ExclusiveExamples {
+			res = append(res, cascadeChange{
+				action: audit.ActionDelete, entityType: audit.EntityExample, entityID: e.ID, before: e,
+			})
+		}
+	}
+
+	return res
+}
+
+// PublishVersion saves current state of documentation with all its published articles and examples
+// as immutable version.
+func (uc *DocUC) PublishVersion(ctx context.Context, docID int, name string) (*doc.Version, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.Contains(name, "/") {
+		return nil, doc.ErrInvalidVersionName
+	}
+
+	d, err := uc.getPublished(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	v := doc.Version{
+		DocID: docID,
+		Name:  name,
+		Doc:   d,
+	}
+
+	err = uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		err := tx.DocVersion().Create(ctx, &v)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Audit().Record(ctx, audit.ActionPublish, audit.EntityVersion, v.ID, nil,
+			map[string]any{"documentation_id": docID, "name": name})
+		if err != nil {
+			return err
+		}
+
+		return tx.Event().Emit(ctx, event.VersionPublished, v.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// getPublished returns current state of documentation with its published articles and examples.
+func (uc *DocUC) getPublished(ctx context.Context, docID int) (*doc.Documentation, error) {
+	d, err := uc.Store.Doc().GetByID(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Articles = article.FilterPublished(d.Articles)
+	for i, a := range d.Articles {
+		art, err := uc.Store.Article().GetByID(ctx, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		art.Examples = example.FilterPublished(art.Examples)
+		art.Backlinks = nil
+
+		d.Articles[i] = *art
+	}
+
+	return d, nil
+}
+
+// GetExport returns content of documentation for offline copy: version with name or current published
+// content with empty version name if name is empty.
+func (uc *DocUC) GetExport(ctx context.Context, docID int, name string) (*doc.Version, error) {
+	if name != "" {
+		return uc.Store.DocVersion().GetByName(ctx, docID, name)
+	}
+
+	d, err := uc.getPublished(ctx, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &doc.Version{DocID: docID, Doc: d}, nil
+}
+
+func (uc *DocUC) GetVersion(ctx context.Context, docID int, name string) (*doc.Version, error) {
+	v, err := uc.Store.DocVersion().GetByName(ctx, docID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.fillVersions(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// GetLatestVersion returns the last published version. It returns nil if documentation has no versions.
+func (uc *DocUC) GetLatestVersion(ctx context.Context, docID int) (*doc.Version, error) {
+	v, err := uc.Store.DocVersion().GetLatest(ctx, docID)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	err = uc.fillVersions(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// fillVersions sets list of all documentation versions to snapshot for switching between them.
+func (uc *DocUC) fillVersions(ctx context.Context, v *doc.Version) error {
+	versions, err := uc.Store.DocVersion().GetByDocID(ctx, v.DocID)
+	if err != nil {
+		return err
+	}
+	v.Doc.Versions = versions
+
+	return nil
 }
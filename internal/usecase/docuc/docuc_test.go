@@ -0,0 +1,36 @@
+package docuc
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/example"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCascadeChanges(t *testing.T) {
+	plan := &doc.DeletePlan{
+		Shared:            []article.Article{{ID: 1}},
+		Exclusive:         []article.Article{{ID: 2}},
+		ExclusiveExamples: []example.Example{{ID: 3}},
+	}
+
+	summary := func(cascade string) []string {
+		res := make([]string, 0)
+		for _, c := range cascadeChanges(plan, cascade) {
+			res = append(res, c.action+" "+c.entityType)
+		}
+		return res
+	}
+
+	assert.Equal(t, []string{"unlink article", "unlink article"}, summary(doc.CascadeUnlink))
+	assert.Equal(t, []string{"unlink article", "delete article"}, summary(doc.CascadeArticles))
+	assert.Equal(t, []string{"unlink article", "delete article", "delete example"}, summary(doc.CascadeExamples))
+
+	changes := cascadeChanges(plan, doc.CascadeExamples)
+	assert.Equal(t, cascadeChange{action: audit.ActionUnlink, entityType: audit.EntityArticle, entityID: 1}, changes[0])
+	assert.Equal(t, plan.Exclusive[0], changes[1].before)
+	assert.Equal(t, 3, changes[2].entityID)
+}
@@ -3,7 +3,13 @@ package exampleuc
 import (
 	"context"
 	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/codefmt"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/event"
 	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/review"
+	"documentation-mini-app/internal/domain/user"
+	"fmt"
 )
 
 type ExampleUC struct {
@@ -14,34 +20,298 @@ func New(store *pgstore.Store) *ExampleUC {
 	return &ExampleUC{Store: store}
 }
 
+// GetExampleByID returns example. Readers get only published example.
 func (uc *ExampleUC) GetExampleByID(ctx context.Context, id int) (*example.Example, error) {
 	exa, err := uc.Store.Example().GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	if !user.IsEditor(ctx) && !exa.Published {
+		return nil, example.ErrNotFound
+	}
+
 	return exa, nil
 }
 
-func (uc *ExampleUC) CreateExample(ctx context.Context, exa *example.Example) error {
-	err := uc.Store.Example().Create(ctx, exa)
-	return err
+// GetExampleForEdit returns content of open revision of current user, if there is one, or the example itself.
+func (uc *ExampleUC) GetExampleForEdit(ctx context.Context, id int) (*example.Example, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, user.ErrAnonymous
+	}
+
+	exa, err := uc.Store.Example().GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := uc.Store.Revision().GetOpen(ctx, review.EntityExample, id, u.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if rev != nil {
+		if rev.Example.Version == 0 {
+			rev.Example.Version = exa.Version
+		}
+		return rev.Example, nil
+	}
+
+	return exa, nil
 }
 
-func (uc *ExampleUC) AddExampleToArticle(ctx context.Context, exaID int, artID int) error {
-	if artID != 0 {
-		err := uc.Store.Example().AddToArticle(ctx, exaID, artID)
+// CreateExample creates unpublished example in article artID and draft revision with its content.
+// It returns article.ErrNotFound if there is no such article.
+// Unless allowDuplicate is set, it returns *example.DuplicateError when examples with the same
+// normalized code exist, so the writer can link one of them instead.
+func (uc *ExampleUC) CreateExample(ctx context.Context, exa *example.Example, artID int, allowDuplicate bool) error {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return user.ErrAnonymous
+	}
+
+	err := uc.prepare(exa)
+	if err != nil {
+		return err
+	}
+
+	exa.Published = false
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		_, err := tx.Article().GetByID(ctx, artID)
+		if err != nil {
+			return err
+		}
+
+		if hash := example.CodeHash(*exa); hash != "" && !allowDuplicate {
+			existing, err := tx.Example().GetByCodeHash(ctx, hash)
+			if err != nil {
+				return err
+			}
+
+			if len(existing) > 0 {
+				return &example.DuplicateError{Existing: existing}
+			}
+		}
+
+		err = tx.Example().Create(ctx, exa)
+		if err != nil {
+			return err
+		}
+
+		content := *exa
+		rev := review.Revision{
+			EntityType: review.EntityExample,
+			EntityID:   exa.ID,
+			Example:    &content,
+			Status:     review.StatusDraft,
+			Author:     u.Name,
+		}
+
+		err = tx.Revision().Create(ctx, &rev)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Audit().Record(ctx, audit.ActionCreate, audit.EntityExample, exa.ID, nil, exa)
+		if err != nil {
+			return err
+		}
+
+		return addToArticle(ctx, tx, exa.ID, artID)
+	})
+}
+
+// LinkExample adds existing example to article. It does nothing if example is already in article.
+func (uc *ExampleUC) LinkExample(ctx context.Context, exaID int, artID int) error {
+	if !user.IsEditor(ctx) {
+		return user.ErrAnonymous
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		_, err := tx.Example().GetByID(ctx, exaID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Article().GetByID(ctx, artID)
+		if err != nil {
+			return err
+		}
+
+		linked, err := tx.Example().InArticle(ctx, exaID, artID)
+		if err != nil || linked {
+			return err
+		}
+
+		return addToArticle(ctx, tx, exaID, artID)
+	})
+}
+
+// FillCodeHashes computes code hashes of examples created before hashes were introduced.
+func (uc *ExampleUC) FillCodeHashes(ctx context.Context) error {
+	ids, err := uc.Store.Example().GetIDsWithoutCodeHash(ctx)
+	if err != nil {
 		return err
 	}
+
+	for _, id := range ids {
+		exa, err := uc.Store.Example().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = uc.Store.Example().SetCodeHash(ctx, id, example.CodeHash(*exa))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-func (uc *ExampleUC) UpdateExample(ctx context.Context, exa *example.Example) error {
-	err := uc.Store.Example().Update(ctx, exa)
-	return err
+func (uc *ExampleUC) AddExampleToArticle(ctx context.Context, exaID int, artID int) error {
+	if artID == 0 {
+		return nil
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		return addToArticle(ctx, tx, exaID, artID)
+	})
+}
+
+// addToArticle links example to article in transaction tx and records it.
+func addToArticle(ctx context.Context, tx *pgstore.Store, exaID int, artID int) error {
+	err := tx.Example().AddToArticle(ctx, exaID, artID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Audit().Record(ctx, audit.ActionLink, audit.EntityExample, exaID, nil,
+		map[string]int{"article_id": artID})
+	if err != nil {
+		return err
+	}
+
+	return tx.Event().Emit(ctx, event.ExampleAdded, exaID)
+}
+
+// SaveExampleDraft saves new content of example into open revision of current user without publishing it.
+// exa.Version must be the version of example the editor started from, otherwise example.ErrConflict is returned.
+func (uc *ExampleUC) SaveExampleDraft(ctx context.Context, exa *example.Example) (*review.Revision, error) {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return nil, user.ErrAnonymous
+	}
+
+	err := uc.prepare(exa)
+	if err != nil {
+		return nil, err
+	}
+
+	var rev *review.Revision
+	err = uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		version, err := tx.Example().LockVersion(ctx, exa.ID)
+		if err != nil {
+			return err
+		}
+
+		if exa.Version != version {
+			return example.ErrConflict
+		}
+
+		rev, err = tx.Revision().GetOpen(ctx, review.EntityExample, exa.ID, u.Name)
+		if err != nil {
+			return err
+		}
+
+		if rev == nil {
+			rev = &review.Revision{
+				EntityType: review.EntityExample,
+				EntityID:   exa.ID,
+				Example:    exa,
+				Status:     review.StatusDraft,
+				Author:     u.Name,
+			}
+
+			err = tx.Revision().Create(ctx, rev)
+			if err != nil {
+				return err
+			}
+
+			return tx.Audit().Record(ctx, audit.ActionCreate, audit.EntityRevision, rev.ID, nil, rev)
+		}
+
+		before := *rev
+
+		err = rev.Edit(u.Name)
+		if err != nil {
+			return err
+		}
+		rev.Example = exa
+
+		err = tx.Revision().Update(ctx, rev)
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, audit.ActionUpdate, audit.EntityRevision, rev.ID, before, rev)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rev, nil
 }
 
+// DeleteExample moves example to trash.
 func (uc *ExampleUC) DeleteExample(ctx context.Context, id int) error {
-	err := uc.Store.Example().Delete(ctx, id)
-	return err
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		before, err := tx.Example().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Example().Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Audit().Record(ctx, audit.ActionDelete, audit.EntityExample, id, before, nil)
+		if err != nil {
+			return err
+		}
+
+		return tx.Event().Emit(ctx, event.ExampleDeleted, id)
+	})
+}
+
+// prepare validates files of example and formats its code by highlight language if example has auto
+// format enabled.
+func (uc *ExampleUC) prepare(exa *example.Example) error {
+	err := exa.ValidateFiles()
+	if err != nil {
+		return err
+	}
+
+	if !exa.AutoFormat {
+		return nil
+	}
+
+	code, err := codefmt.Format(exa.HighlightLanguage, exa.Code)
+	if err != nil {
+		return fmt.Errorf("%w: %v", example.ErrInvalidCode, err)
+	}
+	exa.Code = code
+
+	for i, f := range exa.Files {
+		code, err = codefmt.Format(f.HighlightLanguage, f.Code)
+		if err != nil {
+			return fmt.Errorf("%w: %v: %v", example.ErrInvalidCode, f.Name, err)
+		}
+		exa.Files[i].Code = code
+	}
+
+	return nil
 }
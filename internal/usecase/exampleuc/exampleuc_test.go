@@ -0,0 +1,79 @@
+package exampleuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/user"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testUC(t *testing.T) *ExampleUC {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("need TEST_DATABASE_URL env variable")
+	}
+
+	ctx := context.Background()
+	store, truncate := pgstore.TestStore(ctx, t, dbURL)
+	t.Cleanup(func() {
+		truncate(ctx, "article", "example", "article_examples", "audit_log")
+	})
+
+	return New(store)
+}
+
+func as(name string) context.Context {
+	return user.NewContext(context.Background(), &user.User{Name: name})
+}
+
+func TestLinkExample(t *testing.T) {
+	uc := testUC(t)
+	ctx := as("alice")
+
+	art := &article.Article{Name: "Maps"}
+	require.NoError(t, uc.Store.Article().Create(ctx, art))
+	exa := &example.Example{Name: "make"}
+	require.NoError(t, uc.Store.Example().Create(ctx, exa))
+
+	assert.ErrorIs(t, uc.LinkExample(context.Background(), exa.ID, art.ID), user.ErrAnonymous)
+	assert.ErrorIs(t, uc.LinkExample(ctx, exa.ID+1, art.ID), example.ErrNotFound)
+	assert.ErrorIs(t, uc.LinkExample(ctx, exa.ID, art.ID+1), article.ErrNotFound)
+
+	require.NoError(t, uc.LinkExample(ctx, exa.ID, art.ID))
+	// Linking again does nothing.
+	require.NoError(t, uc.LinkExample(ctx, exa.ID, art.ID))
+
+	exas, err := uc.Store.Example().GetByArticleID(ctx, art.ID)
+	require.NoError(t, err)
+	require.Len(t, exas, 1)
+	assert.Equal(t, exa.ID, exas[0].ID)
+}
+
+func TestExampleVersion(t *testing.T) {
+	uc := testUC(t)
+	ctx := as("alice")
+
+	art := &article.Article{Name: "Maps"}
+	require.NoError(t, uc.Store.Article().Create(ctx, art))
+	exa := &example.Example{Name: "make", Code: "m := map[string]int{}"}
+	require.NoError(t, uc.Store.Example().Create(ctx, exa))
+	require.NoError(t, uc.Store.Example().AddToArticle(ctx, exa.ID, art.ID))
+	require.NoError(t, uc.Store.Example().Update(ctx, exa))
+
+	// Examples listed with article or found by code are edited from their current version.
+	exas, err := uc.Store.Example().GetByArticleID(ctx, art.ID)
+	require.NoError(t, err)
+	require.Len(t, exas, 1)
+	assert.Equal(t, exa.Version, exas[0].Version)
+
+	exas, err = uc.Store.Example().GetByCodeHash(ctx, example.CodeHash(*exa))
+	require.NoError(t, err)
+	require.Len(t, exas, 1)
+	assert.Equal(t, exa.Version, exas[0].Version)
+}
@@ -0,0 +1,86 @@
+package liveuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/event"
+	"log"
+	"sync"
+	"time"
+)
+
+// subscriberBuffer is number of changes kept for subscriber that doesn't read them yet.
+const subscriberBuffer = 16
+
+// subscriber waits for changes of documentation or article page. Zero id means no page.
+type subscriber struct {
+	docID int
+	artID int
+	ch    chan event.Change
+}
+
+// LiveUC delivers changes emitted by usecases of all server instances to open pages.
+type LiveUC struct {
+	Store *pgstore.Store
+
+	mu   sync.Mutex
+	subs map[*subscriber]struct{}
+}
+
+func New(store *pgstore.Store) *LiveUC {
+	return &LiveUC{Store: store, subs: make(map[*subscriber]struct{})}
+}
+
+// Subscribe returns changes shown on page of documentation docID or article artID. Cancel must be called
+// when page is closed.
+func (uc *LiveUC) Subscribe(docID int, artID int) (<-chan event.Change, func()) {
+	s := &subscriber{docID: docID, artID: artID, ch: make(chan event.Change, subscriberBuffer)}
+
+	uc.mu.Lock()
+	uc.subs[s] = struct{}{}
+	uc.mu.Unlock()
+
+	cancel := func() {
+		uc.mu.Lock()
+		delete(uc.subs, s)
+		uc.mu.Unlock()
+	}
+
+	return s.ch, cancel
+}
+
+// Publish sends change to subscribers whose page it affects. Subscriber with full buffer misses the change.
+func (uc *LiveUC) Publish(c event.Change) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	for s := range uc.subs {
+		if (s.docID == 0 || !c.AffectsDoc(s.docID)) && (s.artID == 0 || !c.AffectsArticle(s.artID)) {
+			continue
+		}
+
+		select {
+		case s.ch <- c:
+		default:
+			log.Printf("live subscriber of doc %v article %v missed %v\n", s.docID, s.artID, c.Type)
+		}
+	}
+}
+
+// Run publishes changes of all server instances until ctx is done. It listens again after retry delay
+// if connection fails.
+func (uc *LiveUC) Run(ctx context.Context, retry time.Duration) {
+	for {
+		err := uc.Store.Event().Listen(ctx, uc.Publish)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("listen changes: %v\n", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package overlapuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/crossed"
+	"documentation-mini-app/internal/domain/overlap"
+	"documentation-mini-app/internal/domain/user"
+)
+
+type OverlapUC struct {
+	Store *pgstore.Store
+}
+
+func New(store *pgstore.Store) *OverlapUC {
+	return &OverlapUC{Store: store}
+}
+
+// GetOverlap returns shared content of every pair of documentations and near-duplicate articles.
+// Drafts are included, so report is available only to editors.
+func (uc *OverlapUC) GetOverlap(ctx context.Context) (*overlap.Report, error) {
+	if !user.IsEditor(ctx) {
+		return nil, user.ErrAnonymous
+	}
+
+	arts, err := uc.Store.Article().GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make([]overlap.ArticleContent, 0, len(arts))
+	exaIDs := make(map[int][]int, len(arts))
+	for _, a := range arts {
+		exas, err := uc.Store.Example().GetByArticleID(ctx, a.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		content := overlap.ArticleContent{
+			Article:     crossed.Entry{ID: a.ID, Name: a.Name},
+			Description: a.Description,
+		}
+		for _, exa := range exas {
+			exaIDs[a.ID] = append(exaIDs[a.ID], exa.ID)
+			for _, f := range exa.AllFiles() {
+				content.Code = append(content.Code, f.Code)
+			}
+		}
+		contents = append(contents, content)
+	}
+
+	docs, err := uc.Store.Doc().GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	docContents := make([]overlap.DocContent, 0, len(docs))
+	for _, d := range docs {
+		dc := overlap.DocContent{Doc: crossed.Entry{ID: d.ID, Name: d.Name}}
+		for _, a := range d.Articles {
+			dc.ArticleIDs = append(dc.ArticleIDs, a.ID)
+			dc.ExampleIDs = append(dc.ExampleIDs, exaIDs[a.ID]...)
+		}
+		docContents = append(docContents, dc)
+	}
+
+	return &overlap.Report{
+		Pairs:      overlap.Pairs(docContents),
+		Duplicates: overlap.Duplicates(contents, overlap.DuplicateThreshold),
+	}, nil
+}
@@ -0,0 +1,202 @@
+package reviewuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/event"
+	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/review"
+	"documentation-mini-app/internal/domain/user"
+	"fmt"
+)
+
+type ReviewUC struct {
+	Store *pgstore.Store
+}
+
+func New(store *pgstore.Store) *ReviewUC {
+	return &ReviewUC{Store: store}
+}
+
+func (uc *ReviewUC) GetRevision(ctx context.Context, id int) (*review.Revision, error) {
+	if !user.IsEditor(ctx) {
+		return nil, user.ErrAnonymous
+	}
+
+	return uc.Store.Revision().GetByID(ctx, id)
+}
+
+// GetPublished returns current content of article or example changed by revision, the other one is nil.
+// It is compared with revision when publication fails with conflict.
+func (uc *ReviewUC) GetPublished(ctx context.Context, rev *review.Revision) (*article.Article, *example.Example,
+	error,
+) {
+	if !user.IsEditor(ctx) {
+		return nil, nil, user.ErrAnonymous
+	}
+
+	switch rev.EntityType {
+	case review.EntityArticle:
+		art, err := uc.Store.Article().GetByID(ctx, rev.EntityID)
+		return art, nil, err
+	case review.EntityExample:
+		exa, err := uc.Store.Example().GetByID(ctx, rev.EntityID)
+		return nil, exa, err
+	}
+
+	return nil, nil, fmt.Errorf("unknown revision entity type %q", rev.EntityType)
+}
+
+// GetQueue returns revisions waiting for review or publication.
+func (uc *ReviewUC) GetQueue(ctx context.Context) ([]review.Revision, error) {
+	if !user.IsEditor(ctx) {
+		return nil, user.ErrAnonymous
+	}
+
+	return uc.Store.Revision().GetByStatus(ctx, review.StatusReview, review.StatusApproved)
+}
+
+func (uc *ReviewUC) SubmitRevision(ctx context.Context, id int) error {
+	return uc.transit(ctx, id, audit.ActionSubmit, func(_ *pgstore.Store, rev *review.Revision, u *user.User) error {
+		return rev.Submit(u.Name)
+	})
+}
+
+func (uc *ReviewUC) ApproveRevision(ctx context.Context, id int) error {
+	return uc.transit(ctx, id, audit.ActionApprove, func(_ *pgstore.Store, rev *review.Revision, u *user.User) error {
+		return rev.Approve(u.Name)
+	})
+}
+
+func (uc *ReviewUC) RejectRevision(ctx context.Context, id int) error {
+	return uc.transit(ctx, id, audit.ActionReject, func(_ *pgstore.Store, rev *review.Revision, u *user.User) error {
+		return rev.Reject(u.Name)
+	})
+}
+
+// PublishRevision makes content of approved revision visible to readers.
+func (uc *ReviewUC) PublishRevision(ctx context.Context, id int) error {
+	return uc.transit(ctx, id, audit.ActionPublish, func(tx *pgstore.Store, rev *review.Revision, _ *user.User) error {
+		err := rev.Publish()
+		if err != nil {
+			return err
+		}
+
+		return apply(ctx, tx, rev)
+	})
+}
+
+// transit applies action to revision and records it in audit log with name auditAction in one transaction.
+func (uc *ReviewUC) transit(ctx context.Context, id int, auditAction string,
+	action func(*pgstore.Store, *review.Revision, *user.User) error,
+) error {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return user.ErrAnonymous
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		rev, err := tx.Revision().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		before := *rev
+
+		err = action(tx, rev, u)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Revision().Update(ctx, rev)
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, auditAction, audit.EntityRevision, rev.ID, before, rev)
+	})
+}
+
+// apply writes content of revision to its article or example and publishes it.
+// It fails with conflict error when article or example was changed after revision was last saved.
+func apply(ctx context.Context, tx *pgstore.Store, rev *review.Revision) error {
+	switch rev.EntityType {
+	case review.EntityArticle:
+		art := *rev.Article
+		art.ID = rev.EntityID
+
+		before, err := tx.Article().GetByID(ctx, art.ID)
+		if err != nil {
+			return err
+		}
+		if art.Version == 0 {
+			// Revision was saved before versions were introduced.
+			art.Version = before.Version
+		}
+
+		err = tx.Article().Update(ctx, &art)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Article().SetLinks(ctx, art.ID, article.ParseLinks(art.Description))
+		if err != nil {
+			return err
+		}
+
+		err = tx.Article().SetTags(ctx, art.ID, art.Tags)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Article().SetSlug(ctx, art.ID, art.Name)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Article().SetPublished(ctx, art.ID, true)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Audit().Record(ctx, audit.ActionUpdate, audit.EntityArticle, art.ID, before, art)
+		if err != nil {
+			return err
+		}
+
+		return tx.Event().Emit(ctx, event.ArticlePublished, art.ID)
+	case review.EntityExample:
+		exa := *rev.Example
+		exa.ID = rev.EntityID
+
+		before, err := tx.Example().GetByID(ctx, exa.ID)
+		if err != nil {
+			return err
+		}
+		if exa.Version == 0 {
+			// Revision was saved before versions were introduced.
+			exa.Version = before.Version
+		}
+
+		err = tx.Example().Update(ctx, &exa)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Example().SetPublished(ctx, exa.ID, true)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Audit().Record(ctx, audit.ActionUpdate, audit.EntityExample, exa.ID, before, exa)
+		if err != nil {
+			return err
+		}
+
+		return tx.Event().Emit(ctx, event.ExamplePublished, exa.ID)
+	}
+
+	return fmt.Errorf("unknown revision entity type %q", rev.EntityType)
+}
@@ -0,0 +1,115 @@
+package reviewuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/review"
+	"documentation-mini-app/internal/domain/user"
+	"documentation-mini-app/internal/usecase/articleuc"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testUC(t *testing.T) *ReviewUC {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("need TEST_DATABASE_URL env variable")
+	}
+
+	ctx := context.Background()
+	store, truncate := pgstore.TestStore(ctx, t, dbURL)
+	t.Cleanup(func() {
+		truncate(ctx, "documentation", "article", "example", "revision", "audit_log")
+	})
+
+	return New(store)
+}
+
+func as(name string) context.Context {
+	return user.NewContext(context.Background(), &user.User{Name: name})
+}
+
+// createArticle creates unpublished article by alice and returns it with her draft revision.
+func createArticle(t *testing.T, uc *ReviewUC) (*article.Article, *review.Revision) {
+	art := &article.Article{Name: "Maps", Description: "Text"}
+	require.NoError(t, articleuc.New(uc.Store).CreateArticle(as("alice"), art, 0))
+
+	rev, err := uc.Store.Revision().GetOpen(as("alice"), review.EntityArticle, art.ID, "alice")
+	require.NoError(t, err)
+	require.NotNil(t, rev)
+
+	return art, rev
+}
+
+func TestReviewUC_ApprovePublish(t *testing.T) {
+	uc := testUC(t)
+	art, rev := createArticle(t, uc)
+
+	assert.ErrorIs(t, uc.SubmitRevision(context.Background(), rev.ID), user.ErrAnonymous)
+	assert.ErrorIs(t, uc.SubmitRevision(as("bob"), rev.ID), review.ErrNotAuthor)
+	require.NoError(t, uc.SubmitRevision(as("alice"), rev.ID))
+
+	assert.ErrorIs(t, uc.ApproveRevision(as("alice"), rev.ID), review.ErrSelfApprove)
+	assert.ErrorIs(t, uc.PublishRevision(as("bob"), rev.ID), review.ErrWrongStatus)
+	require.NoError(t, uc.ApproveRevision(as("bob"), rev.ID))
+
+	got, err := uc.Store.Article().GetByID(as("bob"), art.ID)
+	require.NoError(t, err)
+	assert.False(t, got.Published)
+
+	require.NoError(t, uc.PublishRevision(as("bob"), rev.ID))
+	assert.ErrorIs(t, uc.PublishRevision(as("bob"), rev.ID), review.ErrWrongStatus)
+
+	got, err = uc.Store.Article().GetByID(as("bob"), art.ID)
+	require.NoError(t, err)
+	assert.True(t, got.Published)
+	assert.Equal(t, "Maps", got.Name)
+	assert.Equal(t, art.Version+1, got.Version)
+
+	rev, err = uc.GetRevision(as("bob"), rev.ID)
+	require.NoError(t, err)
+	assert.Equal(t, review.StatusPublished, rev.Status)
+	assert.Equal(t, "bob", rev.Reviewer)
+
+	entries, err := uc.Store.Audit().Find(as("bob"), audit.Filter{EntityType: audit.EntityRevision, EntityID: rev.ID})
+	require.NoError(t, err)
+	actions := make([]string, 0, len(entries))
+	for _, e := range entries {
+		actions = append(actions, e.Action+" "+e.Actor)
+	}
+	assert.Equal(t, []string{"publish bob", "approve bob", "submit alice"}, actions)
+}
+
+func TestReviewUC_PublishConflict(t *testing.T) {
+	uc := testUC(t)
+	art, rev := createArticle(t, uc)
+
+	bobRev, err := articleuc.New(uc.Store).SaveArticleDraft(as("bob"), &article.Article{
+		ID: art.ID, Name: "Hash maps", Description: "Other text", Version: art.Version,
+	})
+	require.NoError(t, err)
+
+	for _, r := range []struct {
+		id             int
+		author, editor string
+	}{{rev.ID, "alice", "bob"}, {bobRev.ID, "bob", "alice"}} {
+		require.NoError(t, uc.SubmitRevision(as(r.author), r.id))
+		require.NoError(t, uc.ApproveRevision(as(r.editor), r.id))
+	}
+
+	require.NoError(t, uc.PublishRevision(as("bob"), rev.ID))
+	assert.ErrorIs(t, uc.PublishRevision(as("alice"), bobRev.ID), article.ErrConflict)
+
+	got, err := uc.Store.Article().GetByID(as("alice"), art.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Maps", got.Name)
+
+	bobRev, err = uc.GetRevision(as("alice"), bobRev.ID)
+	require.NoError(t, err)
+	assert.Equal(t, review.StatusApproved, bobRev.Status)
+}
@@ -0,0 +1,53 @@
+package taguc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/example"
+	"documentation-mini-app/internal/domain/tag"
+	"documentation-mini-app/internal/domain/user"
+	"strings"
+)
+
+type TagUC struct {
+	Store *pgstore.Store
+}
+
+func New(store *pgstore.Store) *TagUC {
+	return &TagUC{Store: store}
+}
+
+// GetTags returns tags starting with prefix. Readers get only tags of published articles and examples.
+func (uc *TagUC) GetTags(ctx context.Context, prefix string) ([]tag.Tag, error) {
+	return uc.Store.Tag().GetAll(ctx, tag.Normalize(prefix), !user.IsEditor(ctx))
+}
+
+// GetTagged returns articles and examples marked by tag.
+func (uc *TagUC) GetTagged(ctx context.Context, t string) (*tag.Tagged, error) {
+	return uc.Search(ctx, "", t)
+}
+
+// Search returns articles and examples whose name or description contains query and that are marked by tag.
+// Readers get only published articles and examples.
+func (uc *TagUC) Search(ctx context.Context, query string, t string) (*tag.Tagged, error) {
+	res := tag.Tagged{Tag: tag.Normalize(t), Query: strings.TrimSpace(query)}
+
+	var err error
+	res.Articles, err = uc.Store.Article().Search(ctx, res.Query, res.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	res.Examples, err = uc.Store.Example().Search(ctx, res.Query, res.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsEditor(ctx) {
+		res.Articles = article.FilterPublished(res.Articles)
+		res.Examples = example.FilterPublished(res.Examples)
+	}
+
+	return &res, nil
+}
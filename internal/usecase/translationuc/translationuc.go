@@ -0,0 +1,179 @@
+package translationuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/event"
+	"documentation-mini-app/internal/domain/translation"
+	"documentation-mini-app/internal/domain/user"
+	"errors"
+	"strings"
+)
+
+type TranslationUC struct {
+	Store *pgstore.Store
+}
+
+func New(store *pgstore.Store) *TranslationUC {
+	return &TranslationUC{Store: store}
+}
+
+// GetTranslation returns source article with all its examples and its translation to locale.
+// Translation is nil if article is not translated yet.
+func (uc *TranslationUC) GetTranslation(ctx context.Context, artID int, locale string,
+) (*article.Article, *translation.Translation, error) {
+	if !translation.ValidLocale(locale) {
+		return nil, nil, translation.ErrLocale
+	}
+
+	art, err := uc.Store.Article().GetByID(ctx, artID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t, err := uc.Store.Translation().Get(ctx, artID, locale)
+	if errors.Is(err, translation.ErrNotFound) {
+		return art, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return art, t, nil
+}
+
+// SaveTranslation saves translation made from current versions of article and its examples, so it is
+// not outdated anymore. Saved translation is hidden from readers until it is published by another user.
+// Descriptions of examples which don't belong to article are ignored.
+func (uc *TranslationUC) SaveTranslation(ctx context.Context, t *translation.Translation) error {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return user.ErrAnonymous
+	}
+
+	if !translation.ValidLocale(t.Locale) {
+		return translation.ErrLocale
+	}
+
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return translation.ErrEmptyName
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		art, err := tx.Article().GetByID(ctx, t.ArticleID)
+		if err != nil {
+			return err
+		}
+
+		before, err := tx.Translation().Get(ctx, t.ArticleID, t.Locale)
+		if err != nil && !errors.Is(err, translation.ErrNotFound) {
+			return err
+		}
+
+		exas := make(map[int]translation.Example, len(art.Examples))
+		for _, exa := range art.Examples {
+			e := t.Examples[exa.ID]
+			exas[exa.ID] = translation.Example{
+				ExampleID:     exa.ID,
+				Description:   strings.TrimSpace(e.Description),
+				SourceVersion: exa.Version,
+			}
+		}
+
+		t.Examples = exas
+		t.SourceVersion = art.Version
+		t.Author = u.Name
+
+		err = tx.Translation().Save(ctx, t)
+		if err != nil {
+			return err
+		}
+
+		if before == nil {
+			err = tx.Audit().Record(ctx, audit.ActionCreate, audit.EntityTranslation, t.ArticleID, nil, t)
+		} else {
+			err = tx.Audit().Record(ctx, audit.ActionUpdate, audit.EntityTranslation, t.ArticleID, before, t)
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Event().Emit(ctx, event.ArticleUpdated, t.ArticleID)
+	})
+}
+
+// PublishTranslation shows translation to readers. It returns translation.ErrSelfPublish if current user
+// is author of translation.
+func (uc *TranslationUC) PublishTranslation(ctx context.Context, artID int, locale string) error {
+	u, ok := user.FromContext(ctx)
+	if !ok {
+		return user.ErrAnonymous
+	}
+
+	if !translation.ValidLocale(locale) {
+		return translation.ErrLocale
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		before, err := tx.Translation().Get(ctx, artID, locale)
+		if err != nil {
+			return err
+		}
+
+		if before.Published {
+			return nil
+		}
+
+		if before.Author == u.Name {
+			return translation.ErrSelfPublish
+		}
+
+		err = tx.Translation().Publish(ctx, artID, locale)
+		if err != nil {
+			return err
+		}
+
+		after := *before
+		after.Published = true
+
+		err = tx.Audit().Record(ctx, audit.ActionPublish, audit.EntityTranslation, artID, before, &after)
+		if err != nil {
+			return err
+		}
+
+		return tx.Event().Emit(ctx, event.ArticleUpdated, artID)
+	})
+}
+
+func (uc *TranslationUC) DeleteTranslation(ctx context.Context, artID int, locale string) error {
+	_, ok := user.FromContext(ctx)
+	if !ok {
+		return user.ErrAnonymous
+	}
+
+	if !translation.ValidLocale(locale) {
+		return translation.ErrLocale
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		before, err := tx.Translation().Get(ctx, artID, locale)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Translation().Delete(ctx, artID, locale)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Audit().Record(ctx, audit.ActionDelete, audit.EntityTranslation, artID, before, nil)
+		if err != nil {
+			return err
+		}
+
+		return tx.Event().Emit(ctx, event.ArticleUpdated, artID)
+	})
+}
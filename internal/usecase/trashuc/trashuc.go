@@ -0,0 +1,173 @@
+package trashuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/review"
+	"documentation-mini-app/internal/domain/trash"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+type TrashUC struct {
+	Store *pgstore.Store
+
+	// Retention is period after which deleted items are purged automatically. Zero disables automatic purge.
+	Retention time.Duration
+}
+
+func New(store *pgstore.Store, retention time.Duration) *TrashUC {
+	return &TrashUC{Store: store, Retention: retention}
+}
+
+// GetTrash returns deleted documentations, articles and examples.
+func (uc *TrashUC) GetTrash(ctx context.Context) ([]trash.Item, error) {
+	return uc.Store.Trash().GetAll(ctx)
+}
+
+// GetRetention returns period after which deleted items are purged automatically.
+func (uc *TrashUC) GetRetention() time.Duration {
+	return uc.Retention
+}
+
+// Restore returns item from trash with all its relations to documentations, articles and examples.
+func (uc *TrashUC) Restore(ctx context.Context, entityType string, id int) error {
+	switch entityType {
+	case trash.EntityDoc, trash.EntityArticle, trash.EntityExample:
+	default:
+		return trash.ErrUnknownEntity
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		item, err := tx.Trash().Get(ctx, entityType, id)
+		if err != nil {
+			return err
+		}
+
+		switch entityType {
+		case trash.EntityDoc:
+			err = tx.Doc().Restore(ctx, id)
+		case trash.EntityArticle:
+			err = tx.Article().Restore(ctx, id)
+		case trash.EntityExample:
+			err = tx.Example().Restore(ctx, id)
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, audit.ActionRestore, entityType, id, item, nil)
+	})
+}
+
+// Purge deletes item from trash permanently with its discussions and revisions.
+func (uc *TrashUC) Purge(ctx context.Context, entityType string, id int) error {
+	switch entityType {
+	case trash.EntityDoc, trash.EntityArticle, trash.EntityExample:
+	default:
+		return trash.ErrUnknownEntity
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		item, err := tx.Trash().Get(ctx, entityType, id)
+		if err != nil {
+			return err
+		}
+
+		err = purge(ctx, tx, entityType, id)
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, audit.ActionPurge, entityType, id, item, nil)
+	})
+}
+
+// purge deletes entity with its discussions and revisions.
+func purge(ctx context.Context, tx *pgstore.Store, entityType string, id int) error {
+	switch entityType {
+	case trash.EntityArticle:
+		err := tx.Comment().DeleteByArticleID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Article().Purge(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return tx.Revision().DeleteByEntity(ctx, review.EntityArticle, id)
+	case trash.EntityExample:
+		err := tx.Comment().DeleteByExampleID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Example().Purge(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return tx.Revision().DeleteByEntity(ctx, review.EntityExample, id)
+	}
+
+	return tx.Doc().Purge(ctx, id)
+}
+
+// PurgeExpired purges items that are in trash longer than retention period and returns number of purged ones.
+// Item that fails to be purged is logged and skipped, errors of all such items are returned joined.
+func (uc *TrashUC) PurgeExpired(ctx context.Context) (int, error) {
+	if uc.Retention <= 0 {
+		return 0, nil
+	}
+
+	items, err := uc.Store.Trash().GetDeletedBefore(ctx, time.Now().Add(-uc.Retention))
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	var errs []error
+	for _, item := range items {
+		err = uc.Purge(ctx, item.EntityType, item.ID)
+		if err != nil {
+			err = fmt.Errorf("purge %v %v: %w", item.EntityType, item.ID, err)
+			log.Println(err)
+			errs = append(errs, err)
+			continue
+		}
+		n++
+	}
+
+	return n, errors.Join(errs...)
+}
+
+// RunPurge purges expired items every interval until ctx is done.
+func (uc *TrashUC) RunPurge(ctx context.Context, interval time.Duration) {
+	if uc.Retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := uc.PurgeExpired(ctx)
+		if err != nil {
+			log.Printf("purge trash: %v\n", err)
+		}
+		if n > 0 {
+			log.Printf("purged %v items from trash\n", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
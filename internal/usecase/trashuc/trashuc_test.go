@@ -0,0 +1,103 @@
+package trashuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/trash"
+	"documentation-mini-app/internal/domain/user"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testUC(t *testing.T, retention time.Duration) *TrashUC {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("need TEST_DATABASE_URL env variable")
+	}
+
+	ctx := context.Background()
+	store, truncate := pgstore.TestStore(ctx, t, dbURL)
+	t.Cleanup(func() {
+		truncate(ctx, "documentation", "article", "example", "revision", "audit_log")
+	})
+
+	return New(store, retention)
+}
+
+func testCtx() context.Context {
+	return user.NewContext(context.Background(), &user.User{Name: "alice"})
+}
+
+func TestTrashUC_UnknownEntity(t *testing.T) {
+	uc := New(nil, time.Hour)
+
+	assert.ErrorIs(t, uc.Restore(testCtx(), "user", 1), trash.ErrUnknownEntity)
+	assert.ErrorIs(t, uc.Purge(testCtx(), "user", 1), trash.ErrUnknownEntity)
+}
+
+func TestTrashUC_NoRetention(t *testing.T) {
+	n, err := New(nil, 0).PurgeExpired(testCtx())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestTrashUC_RestorePurge(t *testing.T) {
+	ctx := testCtx()
+	uc := testUC(t, time.Hour)
+
+	art := &article.Article{Name: "Maps"}
+	require.NoError(t, uc.Store.Article().Create(ctx, art))
+	require.NoError(t, uc.Store.Article().Delete(ctx, art.ID))
+
+	items, err := uc.GetTrash(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, trash.EntityArticle, items[0].EntityType)
+
+	require.NoError(t, uc.Restore(ctx, trash.EntityArticle, art.ID))
+	_, err = uc.Store.Article().GetByID(ctx, art.ID)
+	require.NoError(t, err)
+	assert.Error(t, uc.Restore(ctx, trash.EntityArticle, art.ID))
+	assert.Error(t, uc.Purge(ctx, trash.EntityArticle, art.ID), "only items in trash are purged")
+
+	require.NoError(t, uc.Store.Article().Delete(ctx, art.ID))
+	require.NoError(t, uc.Purge(ctx, trash.EntityArticle, art.ID))
+
+	items, err = uc.GetTrash(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+
+	entries, err := uc.Store.Audit().Find(ctx, audit.Filter{EntityType: audit.EntityArticle, EntityID: art.ID})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, audit.ActionPurge, entries[0].Action)
+	assert.Equal(t, audit.ActionRestore, entries[1].Action)
+}
+
+func TestTrashUC_PurgeExpired(t *testing.T) {
+	ctx := testCtx()
+	uc := testUC(t, time.Hour)
+
+	d := &doc.Documentation{Name: "Go"}
+	require.NoError(t, uc.Store.Doc().Create(ctx, d))
+	require.NoError(t, uc.Store.Doc().Delete(ctx, d.ID))
+
+	n, err := uc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "item is kept during retention")
+
+	uc.Retention = time.Nanosecond
+	n, err = uc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = uc.Store.Trash().Get(ctx, trash.EntityDoc, d.ID)
+	assert.Error(t, err)
+}
@@ -0,0 +1,192 @@
+package webhookuc
+
+import (
+	"context"
+	"documentation-mini-app/internal/adapters/pgstore"
+	"documentation-mini-app/internal/domain/audit"
+	"documentation-mini-app/internal/domain/user"
+	"documentation-mini-app/internal/domain/webhook"
+	"log"
+	"strings"
+	"time"
+)
+
+// Delivery batch settings. Lease must be longer than request timeout of sender.
+const (
+	deliveryBatch = 20
+	deliveryLease = time.Minute
+	deliveryLog   = 100
+)
+
+type Sender interface {
+	Send(ctx context.Context, w *webhook.Webhook, d *webhook.Delivery) (int, error)
+}
+
+type WebhookUC struct {
+	Store  *pgstore.Store
+	Sender Sender
+}
+
+func New(store *pgstore.Store, sender Sender) *WebhookUC {
+	return &WebhookUC{Store: store, Sender: sender}
+}
+
+func (uc *WebhookUC) GetWebhooks(ctx context.Context, docID int) ([]webhook.Webhook, error) {
+	if !user.IsEditor(ctx) {
+		return nil, user.ErrAnonymous
+	}
+
+	return uc.Store.Webhook().GetByDocID(ctx, docID)
+}
+
+// GetWebhook returns webhook with its recent deliveries.
+func (uc *WebhookUC) GetWebhook(ctx context.Context, id int) (*webhook.Webhook, []webhook.Delivery, error) {
+	if !user.IsEditor(ctx) {
+		return nil, nil, user.ErrAnonymous
+	}
+
+	w, err := uc.Store.Webhook().GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deliveries, err := uc.Store.Webhook().GetDeliveries(ctx, id, deliveryLog)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return w, deliveries, nil
+}
+
+// CreateWebhook creates webhook of documentation. Random secret is generated if it is empty.
+func (uc *WebhookUC) CreateWebhook(ctx context.Context, w *webhook.Webhook) error {
+	if !user.IsEditor(ctx) {
+		return user.ErrAnonymous
+	}
+
+	w.URL = strings.TrimSpace(w.URL)
+	err := w.Validate()
+	if err != nil {
+		return err
+	}
+
+	_, err = uc.Store.Doc().GetByID(ctx, w.DocID)
+	if err != nil {
+		return err
+	}
+
+	if w.Secret == "" {
+		w.Secret, err = webhook.NewSecret()
+		if err != nil {
+			return err
+		}
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		err := tx.Webhook().Create(ctx, w)
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, audit.ActionCreate, audit.EntityWebhook, w.ID, nil, withoutSecret(w))
+	})
+}
+
+func (uc *WebhookUC) DeleteWebhook(ctx context.Context, id int) error {
+	if !user.IsEditor(ctx) {
+		return user.ErrAnonymous
+	}
+
+	return uc.Store.InTx(ctx, func(tx *pgstore.Store) error {
+		w, err := tx.Webhook().GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Webhook().Delete(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return tx.Audit().Record(ctx, audit.ActionDelete, audit.EntityWebhook, id, withoutSecret(w), nil)
+	})
+}
+
+// RetryDelivery queues failed delivery of webhook again.
+func (uc *WebhookUC) RetryDelivery(ctx context.Context, webhookID int, deliveryID int64) error {
+	if !user.IsEditor(ctx) {
+		return user.ErrAnonymous
+	}
+
+	return uc.Store.Webhook().Retry(ctx, webhookID, deliveryID)
+}
+
+// DeliverDue sends up to batch of pending deliveries which time has come and returns number of sent ones.
+// Deliveries are claimed one by one, so lease has to cover single request only.
+func (uc *WebhookUC) DeliverDue(ctx context.Context) (int, error) {
+	webhooks := make(map[int]*webhook.Webhook)
+	sent := 0
+	for i := 0; i < deliveryBatch; i++ {
+		deliveries, err := uc.Store.Webhook().ClaimDue(ctx, 1, deliveryLease)
+		if err != nil {
+			return sent, err
+		}
+		if len(deliveries) == 0 {
+			break
+		}
+		d := &deliveries[0]
+
+		w, ok := webhooks[d.WebhookID]
+		if !ok {
+			w, err = uc.Store.Webhook().GetByID(ctx, d.WebhookID)
+			if err != nil {
+				return sent, err
+			}
+			webhooks[d.WebhookID] = w
+		}
+
+		code, err := uc.Sender.Send(ctx, w, d)
+		if err != nil {
+			d.Fail(time.Now(), code, err)
+		} else {
+			d.Succeed(time.Now(), code)
+			sent++
+		}
+
+		err = uc.Store.Webhook().SaveAttempt(ctx, d)
+		if err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+// RunDelivery sends due deliveries every interval until ctx is done.
+func (uc *WebhookUC) RunDelivery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := uc.DeliverDue(ctx)
+		if err != nil {
+			log.Printf("deliver webhooks: %v\n", err)
+		} else if n > 0 {
+			log.Printf("delivered %v webhook events\n", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// withoutSecret returns copy of webhook to be recorded in audit log.
+func withoutSecret(w *webhook.Webhook) webhook.Webhook {
+	c := *w
+	c.Secret = ""
+
+	return c
+}
@@ -0,0 +1,136 @@
+package exportview
+
+import (
+	"documentation-mini-app/internal/domain/crossed"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Labels are translated captions of crossed matrix table.
+type Labels struct {
+	Documentation string
+	ArticleID     string
+	Total         string
+	// Sheet is name of xlsx worksheet.
+	Sheet string
+}
+
+// crossedTable returns crossed matrix as table. First two rows are names and IDs of articles,
+// first two columns are names and IDs of documentations, totals are in last row and column.
+func crossedTable(c crossed.Crossed, l Labels) [][]Cell {
+	names := make([]Cell, 0, len(c.Articles)+3)
+	ids := make([]Cell, 0, len(c.Articles)+3)
+	names = append(names, l.Documentation, "ID")
+	ids = append(ids, l.ArticleID, "")
+	for _, a := range c.Articles {
+		names = append(names, a.Name)
+		ids = append(ids, a.ID)
+	}
+	names = append(names, l.Total)
+	ids = append(ids, "")
+
+	table := [][]Cell{names, ids}
+	for _, r := range c.Rows() {
+		row := make([]Cell, 0, len(r.Counts)+3)
+		row = append(row, r.Doc.Name, r.Doc.ID)
+		for _, n := range r.Counts {
+			row = append(row, n)
+		}
+		table = append(table, append(row, r.Total))
+	}
+
+	totals := make([]Cell, 0, len(c.Articles)+3)
+	totals = append(totals, l.Total, "")
+	for _, n := range c.ArticleTotals() {
+		totals = append(totals, n)
+	}
+
+	return append(table, append(totals, c.Total()))
+}
+
+// CrossedToCSV writes crossed matrix with totals as CSV.
+func CrossedToCSV(w io.Writer, c crossed.Crossed, l Labels) error {
+	cw := csv.NewWriter(w)
+
+	for _, row := range crossedTable(c, l) {
+		rec := make([]string, len(row))
+		for i, cell := range row {
+			switch v := cell.(type) {
+			case int:
+				rec[i] = strconv.Itoa(v)
+			case string:
+				rec[i] = csvText(v)
+			}
+		}
+
+		err := cw.Write(rec)
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvText prefixes text that spreadsheets would run as formula with apostrophe.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
+
+// CrossedToXLSX writes crossed matrix with totals as xlsx workbook.
+func CrossedToXLSX(w io.Writer, c crossed.Crossed, l Labels) error {
+	return writeXLSX(w, l.Sheet, crossedTable(c, l))
+}
+
+type crossedJSON struct {
+	Articles      []crossedJSONEntry `json:"articles"`
+	Rows          []crossedJSONRow   `json:"rows"`
+	ArticleTotals []int              `json:"article_totals"`
+	Total         int                `json:"total"`
+}
+
+type crossedJSONEntry struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type crossedJSONRow struct {
+	DocumentationID int    `json:"documentation_id"`
+	Documentation   string `json:"documentation"`
+	Counts          []int  `json:"counts"`
+	Total           int    `json:"total"`
+}
+
+// CrossedToJSON writes crossed matrix with totals as JSON. Counts of rows are in order of articles.
+// JSON has no captions, labels are accepted to match other exports.
+func CrossedToJSON(w io.Writer, c crossed.Crossed, _ Labels) error {
+	res := crossedJSON{
+		Articles:      make([]crossedJSONEntry, 0, len(c.Articles)),
+		Rows:          make([]crossedJSONRow, 0, len(c.Docs)),
+		ArticleTotals: c.ArticleTotals(),
+		Total:         c.Total(),
+	}
+
+	for _, a := range c.Articles {
+		res.Articles = append(res.Articles, crossedJSONEntry{ID: a.ID, Name: a.Name})
+	}
+
+	for _, r := range c.Rows() {
+		res.Rows = append(res.Rows, crossedJSONRow{
+			DocumentationID: r.Doc.ID,
+			Documentation:   r.Doc.Name,
+			Counts:          r.Counts,
+			Total:           r.Total,
+		})
+	}
+
+	return json.NewEncoder(w).Encode(res)
+}
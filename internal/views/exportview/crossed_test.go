@@ -0,0 +1,107 @@
+package exportview
+
+import (
+	"archive/zip"
+	"bytes"
+	"documentation-mini-app/internal/domain/crossed"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCrossed = crossed.Crossed{
+	Docs:     []crossed.Entry{{ID: 1, Name: "go"}, {ID: 2, Name: "sql"}},
+	Articles: []crossed.Entry{{ID: 10, Name: "intro"}, {ID: 11, Name: `"maps" & <sets>`}},
+	Map: map[int]map[int]int{
+		1: {10: 1, 11: 1},
+		2: {10: 1},
+	},
+}
+
+var testLabels = Labels{Documentation: "Документация", ArticleID: "ID статьи", Total: "Итого", Sheet: "Статьи"}
+
+func TestCrossedToCSV(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, CrossedToCSV(&b, testCrossed, testLabels))
+
+	assert.Equal(t, `Документация,ID,intro,"""maps"" & <sets>",Итого
+ID статьи,,10,11,
+go,1,1,1,2
+sql,2,1,0,1
+Итого,,2,1,3
+`, b.String())
+}
+
+func TestCrossedToCSV_Formula(t *testing.T) {
+	c := crossed.Crossed{
+		Docs: []crossed.Entry{{ID: 1, Name: "=HYPERLINK(\"x\")"}},
+		Articles: []crossed.Entry{
+			{ID: 10, Name: "+1"}, {ID: 11, Name: "-x"}, {ID: 12, Name: "@SUM(A1)"}, {ID: 13, Name: "a=b"},
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, CrossedToCSV(&b, c, testLabels))
+
+	assert.Equal(t, `Документация,ID,'+1,'-x,'@SUM(A1),a=b,Итого
+ID статьи,,10,11,12,13,
+"'=HYPERLINK(""x"")",1,0,0,0,0,0
+Итого,,0,0,0,0,0
+`, b.String())
+}
+
+func TestCrossedToJSON(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, CrossedToJSON(&b, testCrossed, testLabels))
+
+	assert.JSONEq(t, `{
+		"articles": [{"id": 10, "name": "intro"}, {"id": 11, "name": "\"maps\" & <sets>"}],
+		"rows": [
+			{"documentation_id": 1, "documentation": "go", "counts": [1, 1], "total": 2},
+			{"documentation_id": 2, "documentation": "sql", "counts": [1, 0], "total": 1}
+		],
+		"article_totals": [2, 1],
+		"total": 3
+	}`, b.String())
+}
+
+func TestCrossedToXLSX(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, CrossedToXLSX(&b, testCrossed, testLabels))
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	require.NoError(t, err)
+
+	var sheet []byte
+	for _, f := range zr.File {
+		if f.Name == "xl/worksheets/sheet1.xml" {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			sheet, err = io.ReadAll(rc)
+			require.NoError(t, err)
+		}
+	}
+
+	assert.Contains(t, string(sheet),
+		`<c r="D1" t="inlineStr"><is><t xml:space="preserve">&#34;maps&#34; &amp; &lt;sets&gt;</t></is></c>`)
+	assert.Contains(t, string(sheet), `<c r="E5"><v>3</v></c>`)
+	assert.Contains(t, string(sheet), `<t xml:space="preserve">Итого</t>`)
+}
+
+func TestSheetName(t *testing.T) {
+	assert.Equal(t, "Статьи", sheetName("Статьи"))
+	assert.Equal(t, "ab", sheetName("[a]:*?/\\b"))
+	assert.Equal(t, "x", sheetName("'x'"))
+	assert.Equal(t, "Sheet1", sheetName("/?"))
+	assert.Equal(t, strings.Repeat("я", maxSheetName), sheetName(strings.Repeat("я", 40)))
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "BA", columnName(52))
+}
@@ -0,0 +1,160 @@
+package exportview
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Cell is value of spreadsheet cell, it is either string or int.
+type Cell any
+
+// xlsxParts are static parts of workbook with one sheet.
+var xlsxParts = []struct{ name, body string }{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Target="xl/workbook.xml" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Target="worksheets/sheet1.xml" ` +
+		`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet"/>` +
+		`</Relationships>`},
+}
+
+// maxSheetName is the longest sheet name Excel accepts.
+const maxSheetName = 31
+
+// sheetName makes name acceptable by Excel: it drops forbidden characters and apostrophes at the edges
+// and cuts name to maxSheetName characters. Empty result is replaced by Sheet1.
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(strings.Trim(name, "'"))
+
+	if r := []rune(name); len(r) > maxSheetName {
+		name = strings.TrimSpace(strings.TrimRight(string(r[:maxSheetName]), "'"))
+	}
+
+	if name == "" {
+		return "Sheet1"
+	}
+
+	return name
+}
+
+// writeXLSX writes rows as the only sheet of xlsx workbook.
+func writeXLSX(w io.Writer, sheet string, rows [][]Cell) error {
+	zw := zip.NewWriter(w)
+
+	for _, p := range xlsxParts {
+		err := writeZipPart(zw, p.name, p.body)
+		if err != nil {
+			return err
+		}
+	}
+
+	var name strings.Builder
+	err := xml.EscapeText(&name, []byte(sheetName(sheet)))
+	if err != nil {
+		return err
+	}
+
+	err = writeZipPart(zw, "xl/workbook.xml", xml.Header+
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+		`<sheets><sheet name="`+name.String()+`" sheetId="1" r:id="rId1"/></sheets></workbook>`)
+	if err != nil {
+		return err
+	}
+
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(fw, xml.Header+
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return err
+	}
+
+	for i, row := range rows {
+		err = writeXLSXRow(fw, i+1, row)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(fw, `</sheetData></worksheet>`)
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeXLSXRow(w io.Writer, num int, row []Cell) error {
+	_, err := fmt.Fprintf(w, `<row r="%d">`, num)
+	if err != nil {
+		return err
+	}
+
+	for i, c := range row {
+		ref := columnName(i) + strconv.Itoa(num)
+
+		switch v := c.(type) {
+		case int:
+			_, err = fmt.Fprintf(w, `<c r="%s"><v>%d</v></c>`, ref, v)
+		default:
+			_, err = fmt.Fprintf(w, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			if err == nil {
+				err = xml.EscapeText(w, []byte(fmt.Sprint(v)))
+			}
+			if err == nil {
+				_, err = io.WriteString(w, `</t></is></c>`)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, `</row>`)
+	return err
+}
+
+// columnName returns spreadsheet name of column by its zero based index: A, B, ..., Z, AA, AB and so on.
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+
+	return name
+}
+
+func writeZipPart(zw *zip.Writer, name string, body string) error {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(fw, body)
+	return err
+}
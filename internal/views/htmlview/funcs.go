@@ -0,0 +1,110 @@
+package htmlview
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/i18n"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+)
+
+var funcs = template.FuncMap{
+	"join":       strings.Join,
+	"pathescape": url.PathEscape,
+	"crumbs":     crumbs,
+	"unlink":     unlink,
+}
+
+// localeFuncs returns functions which translate to locale:
+// t "key" args... translates message, tn "key" n args... chooses its plural form for n,
+// lang is locale itself and locales are all supported locales.
+func localeFuncs(b *i18n.Bundle, loc string) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, args ...interface{}) string {
+			return b.T(loc, key, args...)
+		},
+		"tn": func(key string, n int, args ...interface{}) string {
+			return b.N(loc, key, n, args...)
+		},
+		"lang": func() string {
+			return loc
+		},
+		"locales": b.Locales,
+		"linkify": func(text string, links []article.Link) template.HTML {
+			return linkify(text, links, b.T(loc, "article.not_found"), articleURL)
+		},
+		"linkifyVersion": func(text string, links []article.Link, v *doc.Version) template.HTML {
+			return linkify(text, links, b.T(loc, "article.not_found"), versionArticleURL(v))
+		},
+	}
+}
+
+// Crumb is link to parent page in breadcrumbs.
+type Crumb struct {
+	URL  string
+	Name string
+}
+
+// crumbs makes breadcrumbs from pairs of URL and name, e.g. crumbs "/reviews" "Очередь ревью".
+func crumbs(pairs ...string) ([]Crumb, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("crumbs: odd count of arguments %d", len(pairs))
+	}
+
+	res := make([]Crumb, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		res = append(res, Crumb{URL: pairs[i], Name: pairs[i+1]})
+	}
+
+	return res, nil
+}
+
+// linkify escapes text and renders article references as links to URLs returned by href. Broken references are
+// marked by broken-link class and notFound title.
+func linkify(text string, links []article.Link, notFound string, href func(id int) string) template.HTML {
+	ids := make(map[article.Link]int, len(links))
+	for _, l := range links {
+		id := l.ArticleID
+		l.ArticleID = 0
+		ids[l] = id
+	}
+
+	res := article.RenderLinks(text, template.HTMLEscapeString, func(raw string, l article.Link) string {
+		id := ids[l]
+		if id == 0 {
+			return fmt.Sprintf(`<span class="broken-link" title="%s">%s</span>`,
+				template.HTMLEscapeString(notFound), template.HTMLEscapeString(raw))
+		}
+
+		return fmt.Sprintf(`<a href="%s">%s</a>`, template.HTMLEscapeString(href(id)),
+			template.HTMLEscapeString(l.ArticleName))
+	})
+
+	return template.HTML(res) //nolint:gosec // every part of text is escaped above
+}
+
+func articleURL(id int) string {
+	return fmt.Sprintf("/articles/%d", id)
+}
+
+// versionArticleURL returns href of references on article page of version v: articles of the version stay in it,
+// references to other documentations lead to their current articles.
+func versionArticleURL(v *doc.Version) func(id int) string {
+	return func(id int) string {
+		if _, ok := v.Doc.ArticleByID(id); ok {
+			return fmt.Sprintf("/documentations/%d/v/%s/articles/%d", v.DocID, url.PathEscape(v.Name), id)
+		}
+
+		return articleURL(id)
+	}
+}
+
+// unlink replaces article references in text with names of articles, it is used in offline copies
+// where article pages are not available.
+func unlink(text string) string {
+	return article.RenderLinks(text, func(s string) string { return s }, func(_ string, l article.Link) string {
+		return l.ArticleName
+	})
+}
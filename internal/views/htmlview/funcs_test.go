@@ -0,0 +1,25 @@
+package htmlview
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/doc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkify(t *testing.T) {
+	text := "See [[Maps]], [[Net/TCP]] and [[Missing]] <b>"
+	links := article.ParseLinks(text)
+	links[0].ArticleID = 1
+	links[1].ArticleID = 7
+
+	assert.Equal(t, `See <a href="/articles/1">Maps</a>, <a href="/articles/7">TCP</a> and `+
+		`<span class="broken-link" title="not found">[[Missing]]</span> &lt;b&gt;`,
+		string(linkify(text, links, "not found", articleURL)))
+
+	v := &doc.Version{DocID: 2, Name: "v 1", Doc: &doc.Documentation{Articles: []article.Article{{ID: 1}}}}
+	assert.Equal(t, `See <a href="/documentations/2/v/v%201/articles/1">Maps</a>, <a href="/articles/7">TCP</a> and `+
+		`<span class="broken-link" title="not found">[[Missing]]</span> &lt;b&gt;`,
+		string(linkify(text, links, "not found", versionArticleURL(v))))
+}
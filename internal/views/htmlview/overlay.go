@@ -0,0 +1,66 @@
+package htmlview
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+)
+
+// overlayFS opens files from top and falls back to base for files missing in top.
+type overlayFS struct {
+	top  fs.FS
+	base fs.FS
+}
+
+// Overlay returns file system where files of top replace files of base with the same path, so only
+// customized files need to be in top.
+func Overlay(top fs.FS, base fs.FS) fs.FS {
+	return overlayFS{top: top, base: base}
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.top.Open(name)
+	if err == nil {
+		return f, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return o.base.Open(name)
+}
+
+// ReadDir merges entries of both file systems, so walking and globbing see files of base
+// even when top has the same directory.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	top, topErr := fs.ReadDir(o.top, name)
+	if topErr != nil && !errors.Is(topErr, fs.ErrNotExist) {
+		return nil, topErr
+	}
+
+	base, baseErr := fs.ReadDir(o.base, name)
+	if baseErr != nil && !errors.Is(baseErr, fs.ErrNotExist) {
+		return nil, baseErr
+	}
+	if topErr != nil && baseErr != nil {
+		return nil, baseErr
+	}
+
+	entries := make(map[string]fs.DirEntry, len(top)+len(base))
+	for _, e := range base {
+		entries[e.Name()] = e
+	}
+	for _, e := range top {
+		entries[e.Name()] = e
+	}
+
+	res := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name() < res[j].Name()
+	})
+
+	return res, nil
+}
@@ -0,0 +1,278 @@
+package htmlview
+
+import (
+	"context"
+	"documentation-mini-app/internal/i18n"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// layoutFile defines template "layout" with blocks "title", "head" and "content" which pages fill.
+	layoutFile = "layout.html"
+	// partialsDir contains templates shared by pages, e.g. "header" and "example_card".
+	partialsDir = "partials"
+)
+
+// devErrorPage is shown in dev mode instead of pages while templates don't parse.
+var devErrorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html lang="{{ .Lang }}">
+<head>
+    <meta charset="UTF-8">
+    <title>{{ .Title }}</title>
+</head>
+<body>
+    <h1>{{ .Title }}</h1>
+    <pre>{{ .Err }}</pre>
+    <p>{{ .Hint }}</p>
+</body>
+</html>
+`))
+
+// devError is data of devErrorPage translated to locale Lang.
+type devError struct {
+	Lang  string
+	Title string
+	Hint  string
+	Err   string
+}
+
+// Registry holds every page of templates directory parsed together with layout and partials.
+// Page name is path of its file without extension, e.g. "docs/get_doc". Every page has copy per locale
+// with translation functions of that locale, see localeFuncs.
+type Registry struct {
+	fsys   fs.FS
+	bundle *i18n.Bundle
+	// dev enables reload of changed templates, see NewDevRegistry.
+	dev bool
+
+	mu    sync.RWMutex
+	base  *template.Template
+	pages map[string]localized
+	// stamps are modification times of template files at last parse.
+	stamps map[string]time.Time
+	// err is error of last parse in dev mode, it is shown instead of pages until templates are fixed.
+	err error
+}
+
+// localized is page by locale.
+type localized map[string]*template.Template
+
+// NewRegistry parses all pages in fsys at once, so broken template fails startup instead of request.
+func NewRegistry(fsys fs.FS, bundle *i18n.Bundle) (*Registry, error) {
+	r := &Registry{fsys: fsys, bundle: bundle}
+
+	err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// NewDevRegistry returns registry for development. Before every render it checks modification times
+// of templates and re-parses changed ones, parse errors are rendered in browser instead of pages.
+func NewDevRegistry(fsys fs.FS, bundle *i18n.Bundle) *Registry {
+	r := &Registry{fsys: fsys, bundle: bundle, dev: true}
+	r.err = r.load()
+
+	return r
+}
+
+// Render writes page with data inside layout in locale of ctx.
+func (r *Registry) Render(ctx context.Context, w io.Writer, name string, data interface{}) error {
+	if r.dev {
+		err := r.reload()
+		if err != nil {
+			return r.renderDevError(ctx, w, err)
+		}
+	}
+
+	r.mu.RLock()
+	pages, ok := r.pages[name]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown page %q", name)
+	}
+
+	page, ok := pages[i18n.FromContext(ctx)]
+	if !ok {
+		page = pages[i18n.Default]
+	}
+
+	return page.ExecuteTemplate(w, "layout", data)
+}
+
+// renderDevError writes devErrorPage with parse error err. Status of response is 500 if w is http.ResponseWriter.
+func (r *Registry) renderDevError(ctx context.Context, w io.Writer, err error) error {
+	if rw, ok := w.(http.ResponseWriter); ok {
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+
+	loc := i18n.FromContext(ctx)
+	if !r.bundle.Supported(loc) {
+		loc = i18n.Default
+	}
+
+	return devErrorPage.Execute(w, devError{
+		Lang:  loc,
+		Title: r.bundle.T(loc, "template.error"),
+		Hint:  r.bundle.T(loc, "template.error_hint"),
+		Err:   err.Error(),
+	})
+}
+
+// Supported reports whether pages can be rendered in locale.
+func (r *Registry) Supported(loc string) bool {
+	return r.bundle.Supported(loc)
+}
+
+// T translates message of key to locale of ctx for texts rendered outside templates, e.g. in exported files.
+func (r *Registry) T(ctx context.Context, key string, args ...interface{}) string {
+	return r.bundle.T(i18n.FromContext(ctx), key, args...)
+}
+
+// load parses layout, partials and every page.
+func (r *Registry) load() error {
+	stamps, err := r.scan()
+	if err != nil {
+		return err
+	}
+
+	base, err := template.New(layoutFile).Funcs(funcs).Funcs(localeFuncs(r.bundle, i18n.Default)).
+		ParseFS(r.fsys, layoutFile, partialsDir+"/*.html")
+	if err != nil {
+		return err
+	}
+
+	pages := make(map[string]localized)
+	for p := range stamps {
+		if !isPage(p) {
+			continue
+		}
+
+		pages[pageName(p)], err = r.parsePage(base, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.base, r.pages, r.stamps = base, pages, stamps
+	return nil
+}
+
+// reload re-parses templates changed since last parse. Change of layout or partials and removed files
+// cause parse of everything, new or changed page is parsed alone.
+func (r *Registry) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamps, err := r.scan()
+	if err != nil {
+		return err
+	}
+
+	var changed []string
+	full := r.err != nil
+	for p, t := range stamps {
+		old, ok := r.stamps[p]
+		if !ok || !old.Equal(t) {
+			changed = append(changed, p)
+			full = full || !isPage(p)
+		}
+	}
+
+	removed := false
+	for p := range r.stamps {
+		if _, ok := stamps[p]; !ok {
+			removed = true
+		}
+	}
+
+	if len(changed) == 0 && !removed {
+		return r.err
+	}
+
+	r.stamps = stamps
+	if full || removed {
+		r.err = r.load()
+		return r.err
+	}
+
+	for _, p := range changed {
+		page, err := r.parsePage(r.base, p)
+		if err != nil {
+			r.err = err
+			return err
+		}
+
+		r.pages[pageName(p)] = page
+	}
+
+	return nil
+}
+
+// scan returns modification times of all template files.
+func (r *Registry) scan() (map[string]time.Time, error) {
+	stamps := make(map[string]time.Time)
+	err := fs.WalkDir(r.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != ".html" {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		stamps[p] = info.ModTime()
+		return nil
+	})
+
+	return stamps, err
+}
+
+// parsePage parses page file p on top of layout and partials and copies it for every locale.
+func (r *Registry) parsePage(base *template.Template, p string) (localized, error) {
+	page, err := base.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	page, err = page.ParseFS(r.fsys, p)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(localized)
+	for _, loc := range r.bundle.Locales() {
+		l, err := page.Clone()
+		if err != nil {
+			return nil, err
+		}
+
+		res[loc] = l.Funcs(localeFuncs(r.bundle, loc))
+	}
+
+	return res, nil
+}
+
+// isPage reports whether template file is page rather than layout or partial.
+func isPage(p string) bool {
+	return p != layoutFile && !strings.HasPrefix(p, partialsDir+"/")
+}
+
+func pageName(p string) string {
+	return strings.TrimSuffix(p, ".html")
+}
@@ -0,0 +1,133 @@
+package htmlview
+
+import (
+	"bytes"
+	"context"
+	"documentation-mini-app/internal/i18n"
+	"documentation-mini-app/templates"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTemplates = fstest.MapFS{
+	"layout.html": {
+		Data: []byte(`{{ define "layout" }}[{{ template "header" }}|{{ block "content" . }}{{ end }}]{{ end }}`),
+	},
+	"partials/header.html": {Data: []byte(`{{ define "header" }}nav{{ end }}`)},
+	"docs/get_doc.html":    {Data: []byte(`{{ define "content" }}doc {{ . }}{{ end }}`)},
+	"login.html":           {Data: []byte(`{{ define "content" }}login{{ end }}`)},
+	"tags/list_tags.html": {
+		Data: []byte(`{{ define "content" }}{{ t "nav.tags" }} {{ tn "thread.replies" . }}{{ end }}`),
+	},
+}
+
+func testBundle(t *testing.T) *i18n.Bundle {
+	b, err := i18n.New()
+	require.NoError(t, err)
+
+	return b
+}
+
+func TestRegistry(t *testing.T) {
+	views, err := NewRegistry(testTemplates, testBundle(t))
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, views.Render(context.Background(), &b, "docs/get_doc", "Go"))
+	assert.Equal(t, "[nav|doc Go]", b.String())
+
+	b.Reset()
+	require.NoError(t, views.Render(context.Background(), &b, "login", nil))
+	assert.Equal(t, "[nav|login]", b.String())
+
+	assert.Error(t, views.Render(context.Background(), &b, "partials/header", nil))
+}
+
+func TestRegistryLocale(t *testing.T) {
+	views, err := NewRegistry(testTemplates, testBundle(t))
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, views.Render(i18n.NewContext(context.Background(), i18n.English), &b, "tags/list_tags", 2))
+	assert.Equal(t, "[nav|Tags 2 replies]", b.String())
+
+	b.Reset()
+	require.NoError(t, views.Render(context.Background(), &b, "tags/list_tags", 2))
+	assert.Equal(t, "[nav|Теги 2 ответа]", b.String())
+
+	assert.Equal(t, "Contents", views.T(i18n.NewContext(context.Background(), i18n.English), "export.contents"))
+	assert.Equal(t, "Версия v1", views.T(context.Background(), "export.version", "v1"))
+}
+
+func TestRegistryOverlay(t *testing.T) {
+	top := fstest.MapFS{
+		"partials/header.html": {Data: []byte(`{{ define "header" }}custom{{ end }}`)},
+	}
+
+	views, err := NewRegistry(Overlay(top, testTemplates), testBundle(t))
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, views.Render(context.Background(), &b, "docs/get_doc", "Go"))
+	assert.Equal(t, "[custom|doc Go]", b.String())
+}
+
+func TestRegistryTemplates(t *testing.T) {
+	_, err := NewRegistry(templates.FS, testBundle(t))
+	assert.NoError(t, err)
+}
+
+func TestDevRegistry(t *testing.T) {
+	dir := t.TempDir()
+	stamp := time.Now()
+	write := func(name string, text string) {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(text), 0o600))
+		// Modification time is moved explicitly, file system may keep it with low precision.
+		stamp = stamp.Add(time.Second)
+		require.NoError(t, os.Chtimes(p, stamp, stamp))
+	}
+	for name, f := range testTemplates {
+		write(name, string(f.Data))
+	}
+
+	views := NewDevRegistry(os.DirFS(dir), testBundle(t))
+	var b bytes.Buffer
+	require.NoError(t, views.Render(context.Background(), &b, "login", nil))
+	assert.Equal(t, "[nav|login]", b.String())
+
+	write("login.html", `{{ define "content" }}sign in{{ end }}`)
+	b.Reset()
+	require.NoError(t, views.Render(context.Background(), &b, "login", nil))
+	assert.Equal(t, "[nav|sign in]", b.String())
+
+	write("partials/header.html", `{{ define "header" }}{{ if }}{{ end }}`)
+	b.Reset()
+	require.NoError(t, views.Render(context.Background(), &b, "login", nil))
+	assert.Contains(t, b.String(), "Ошибка шаблона")
+
+	rec := httptest.NewRecorder()
+	require.NoError(t, views.Render(i18n.NewContext(context.Background(), i18n.English), rec, "login", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), `<html lang="en">`)
+	assert.Contains(t, rec.Body.String(), "Template error")
+
+	// Broken templates don't fail start in dev mode.
+	b.Reset()
+	require.NoError(t, NewDevRegistry(os.DirFS(dir), testBundle(t)).Render(context.Background(), &b, "login", nil))
+	assert.Contains(t, b.String(), "Ошибка шаблона")
+
+	write("partials/header.html", `{{ define "header" }}menu{{ end }}`)
+	b.Reset()
+	require.NoError(t, views.Render(context.Background(), &b, "docs/get_doc", "Go"))
+	assert.Equal(t, "[menu|doc Go]", b.String())
+}
@@ -0,0 +1,292 @@
+package pdfview
+
+import (
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/example"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Labels are translated captions of document parts.
+type Labels struct {
+	Contents string
+	Examples string
+	Output   string
+	// Subtitle is shown under documentation name on title page, e.g. version name.
+	Subtitle string
+}
+
+var (
+	titleStyle    = style{face: bold, size: 26, leading: 34}
+	subtitleStyle = style{face: regular, size: 14, leading: 20}
+	headingStyle  = style{face: bold, size: 18, leading: 26}
+	sectionStyle  = style{face: bold, size: 14, leading: 20}
+	exampleStyle  = style{face: bold, size: 12, leading: 17}
+	labelStyle    = style{face: bold, size: 9.5, leading: 14}
+	textStyle     = style{face: regular, size: 10.5, leading: 15}
+	tocStyle      = style{face: regular, size: 11, leading: 18}
+	codeStyle     = style{face: mono, size: 9, leading: 12}
+	footerStyle   = style{face: regular, size: 9, leading: 12}
+)
+
+var (
+	fontsOnce sync.Once
+	fonts     [faces]*font
+	fontsErr  error
+)
+
+// loadFonts parses embedded fonts once, they are shared by all documents.
+func loadFonts() (*[faces]*font, error) {
+	fontsOnce.Do(func() {
+		for i, name := range fontFileNames {
+			fonts[i], fontsErr = loadFont(name)
+			if fontsErr != nil {
+				return
+			}
+		}
+	})
+
+	return &fonts, fontsErr
+}
+
+// entry is article in table of contents and outline.
+type entry struct {
+	name string
+	to   anchor
+}
+
+// DocToWriter writes documentation with its articles and examples into w as A4 PDF document. Document has
+// title page, table of contents and every article starting on new page.
+func DocToWriter(w io.Writer, d *doc.Documentation, l Labels) error {
+	fs, err := loadFonts()
+	if err != nil {
+		return err
+	}
+
+	t := newTypesetter(fs)
+	entries := make([]entry, 0, len(d.Articles))
+	for _, art := range d.Articles {
+		t.newPage()
+		entries = append(entries, entry{name: art.Name, to: t.anchor()})
+		t.article(art, l)
+	}
+	body := t.pages
+
+	// Page numbers of articles depend on length of table of contents, so it is typeset once to count its pages.
+	dry := newTypesetter(fs)
+	dry.contents(entries, l.Contents, func(anchor) int { return 0 })
+	front := 1 + len(dry.pages)
+
+	t.pages = nil
+	t.titlePage(d.Name, l.Subtitle)
+	t.contents(entries, l.Contents, func(a anchor) int {
+		for i, p := range body {
+			if p == a.page {
+				return front + i + 1
+			}
+		}
+		return 0
+	})
+	t.pages = append(t.pages, body...)
+
+	for i, p := range t.pages[1:] {
+		num := strconv.Itoa(i + 2)
+		x := (pageWidth - fs[footerStyle.face].width(num, footerStyle.size)) / 2
+		t.draw(p, footerStyle, x, footerY, num)
+	}
+
+	return t.write(w, d.Name, entries)
+}
+
+func (t *typesetter) titlePage(name, subtitle string) {
+	t.newPage()
+	t.y = pageHeight * 0.62
+
+	for _, l := range t.wrapWords(titleStyle, contentWidth, name) {
+		t.centered(titleStyle, l)
+	}
+	if subtitle != "" {
+		t.y -= subtitleStyle.leading
+		t.centered(subtitleStyle, subtitle)
+	}
+}
+
+// centered places one line of text in the middle of free space width.
+func (t *typesetter) centered(s style, text string) {
+	x := (pageWidth - t.fonts[s.face].width(text, s.size)) / 2
+	t.draw(t.page(), s, x, t.baseline(s), text)
+	t.y -= s.leading
+}
+
+// contents places table of contents with dot leaders, page numbers of entries are returned by pageOf.
+// Entries are single lines, so number of pages doesn't depend on page numbers.
+func (t *typesetter) contents(entries []entry, title string, pageOf func(anchor) int) {
+	t.newPage()
+	t.line(headingStyle, 0, title)
+	t.space(textStyle.leading)
+
+	f := t.fonts[tocStyle.face]
+	dot := f.width(".", tocStyle.size)
+	for _, e := range entries {
+		num := strconv.Itoa(pageOf(e.to))
+		numWidth := f.width(num, tocStyle.size)
+		name := t.truncate(tocStyle, contentWidth-numWidth-6*dot, e.name)
+		nameWidth := f.width(name, tocStyle.size)
+		dots := strings.Repeat(".", int(math.Max(0, (contentWidth-nameWidth-numWidth-2*dot)/dot)))
+
+		t.ensure(tocStyle.leading)
+		p, base := t.page(), t.baseline(tocStyle)
+		t.draw(p, tocStyle, margin, base, name)
+		t.draw(p, tocStyle, margin+contentWidth-numWidth-dot-f.width(dots, tocStyle.size), base, dots)
+		t.draw(p, tocStyle, margin+contentWidth-numWidth, base, num)
+		p.links = append(p.links, link{
+			x: margin, y: t.y - tocStyle.leading, w: contentWidth, h: tocStyle.leading, to: e.to,
+		})
+		t.y -= tocStyle.leading
+	}
+}
+
+// article places article on pages starting at current position. References to other articles are
+// written as their names.
+func (t *typesetter) article(art article.Article, l Labels) {
+	t.paragraph(headingStyle, art.Name)
+	t.space(textStyle.leading / 2)
+
+	if art.Description != "" {
+		t.paragraph(textStyle, plainLinks(art.Description))
+	}
+
+	if len(art.Examples) == 0 {
+		return
+	}
+
+	t.space(textStyle.leading)
+	t.keep(sectionStyle)
+	t.line(sectionStyle, 0, l.Examples)
+	for _, exa := range art.Examples {
+		t.example(exa, l)
+	}
+}
+
+func (t *typesetter) example(exa example.Example, l Labels) {
+	t.space(textStyle.leading)
+	t.keep(exampleStyle)
+	t.paragraph(exampleStyle, exa.Name)
+	if exa.Description != "" {
+		t.paragraph(textStyle, plainLinks(exa.Description))
+	}
+
+	files := exa.AllFiles()
+	for _, f := range files {
+		t.space(codeStyle.leading / 2)
+		if len(files) > 1 {
+			t.keep(labelStyle)
+			t.line(labelStyle, 0, f.Name)
+		}
+		t.code(codeStyle, f.Code)
+	}
+
+	if exa.Output != "" {
+		t.space(codeStyle.leading / 2)
+		t.keep(labelStyle)
+		t.line(labelStyle, 0, l.Output)
+		t.code(codeStyle, exa.Output)
+	}
+}
+
+// keep starts new page if heading of style can't be followed by a few lines of text on the current one.
+func (t *typesetter) keep(heading style) {
+	t.ensure(heading.leading + 3*textStyle.leading)
+}
+
+// plainLinks replaces references to articles with their names.
+func plainLinks(text string) string {
+	return article.RenderLinks(text, func(s string) string { return s }, func(_ string, l article.Link) string {
+		return l.ArticleName
+	})
+}
+
+// write writes typeset pages with fonts, links and outline of entries into w.
+func (t *typesetter) write(w io.Writer, title string, entries []entry) error {
+	ow := newObjWriter()
+	catalogID, infoID, pagesID := ow.alloc(), ow.alloc(), ow.alloc()
+
+	var fontRefs strings.Builder
+	for face, used := range t.used {
+		if len(used) == 0 {
+			continue
+		}
+
+		id, err := ow.embedFont(t.fonts[face], used)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&fontRefs, "/F%d %s ", face, ref(id))
+	}
+
+	for _, p := range t.pages {
+		p.id = ow.alloc()
+	}
+
+	kids := make([]string, 0, len(t.pages))
+	for _, p := range t.pages {
+		contentID := ow.alloc()
+		err := ow.stream(contentID, "", p.content.Bytes())
+		if err != nil {
+			return err
+		}
+
+		annots := make([]string, 0, len(p.links))
+		for _, l := range p.links {
+			annots = append(annots, fmt.Sprintf("<< /Type /Annot /Subtype /Link /Rect [%.2f %.2f %.2f %.2f] "+
+				"/Border [0 0 0] /Dest %s >>", l.x, l.y, l.x+l.w, l.y+l.h, dest(l.to)))
+		}
+
+		ow.object(p.id, fmt.Sprintf("<< /Type /Page /Parent %s /Resources << /Font << %s>> >> "+
+			"/Contents %s /Annots [%s] >>", ref(pagesID), fontRefs.String(), ref(contentID),
+			strings.Join(annots, " ")))
+		kids = append(kids, ref(p.id))
+	}
+
+	ow.object(pagesID, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %.2f %.2f] >>",
+		strings.Join(kids, " "), len(kids), pageWidth, pageHeight))
+
+	outline := ""
+	if len(entries) != 0 {
+		outlineID := ow.alloc()
+		ids := make([]int, len(entries))
+		for i := range entries {
+			ids[i] = ow.alloc()
+		}
+
+		for i, e := range entries {
+			item := fmt.Sprintf("/Title %s /Parent %s /Dest %s", textString(e.name), ref(outlineID), dest(e.to))
+			if i > 0 {
+				item += " /Prev " + ref(ids[i-1])
+			}
+			if i < len(entries)-1 {
+				item += " /Next " + ref(ids[i+1])
+			}
+			ow.object(ids[i], "<< "+item+" >>")
+		}
+
+		ow.object(outlineID, fmt.Sprintf("<< /Type /Outlines /First %s /Last %s /Count %d >>",
+			ref(ids[0]), ref(ids[len(ids)-1]), len(ids)))
+		outline = fmt.Sprintf(" /Outlines %s /PageMode /UseOutlines", ref(outlineID))
+	}
+
+	ow.object(catalogID, fmt.Sprintf("<< /Type /Catalog /Pages %s%s >>", ref(pagesID), outline))
+	ow.object(infoID, fmt.Sprintf("<< /Title %s /Producer (documentation-mini-app) >>", textString(title)))
+
+	return ow.finish(w, catalogID, infoID)
+}
+
+// dest returns destination showing page of anchor scrolled to it.
+func dest(a anchor) string {
+	return fmt.Sprintf("[%s /XYZ 0 %.2f null]", ref(a.page.id), a.y)
+}
@@ -0,0 +1,86 @@
+package pdfview
+
+import (
+	"bytes"
+	"documentation-mini-app/internal/domain/article"
+	"documentation-mini-app/internal/domain/doc"
+	"documentation-mini-app/internal/domain/example"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testDoc = doc.Documentation{
+	Name: "Документация Go",
+	Articles: []article.Article{
+		{Name: "Введение", Description: "Смотри [[Карты]].\r\nВторая строка."},
+		{Name: "Карты", Description: strings.Repeat("Длинный текст статьи ", 300), Examples: []example.Example{
+			{Name: "hello", HighlightLanguage: "go", Code: "package main\n\tfunc main() {}", Output: "hello"},
+			{Name: "module", Files: []example.File{{Name: "go.mod", Code: "module x"}, {Name: "main.go"}}},
+		}},
+	},
+}
+
+func TestDocToWriter(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, DocToWriter(&b, &testDoc, Labels{Contents: "Содержание", Examples: "Примеры", Output: "Вывод"}))
+	pdf := b.String()
+
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.7\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+
+	start := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	require.NotNil(t, start)
+	xref, err := strconv.Atoi(start[1])
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(pdf[xref:], "xref\n"))
+
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf[xref:], -1)
+	require.NotEmpty(t, offsets)
+	for i, m := range offsets {
+		off, err := strconv.Atoi(m[1])
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(pdf[off:], strconv.Itoa(i+1)+" 0 obj\n"), "object %d", i+1)
+	}
+
+	// Title, contents, short article and long article on several pages.
+	pages := strings.Count(pdf, "/Type /Page ")
+	assert.Greater(t, pages, 4)
+	assert.Contains(t, pdf, "/Count "+strconv.Itoa(pages))
+	assert.Contains(t, pdf, "/Type /Outlines")
+	assert.Equal(t, 2, strings.Count(pdf, "/Subtype /Link"))
+	assert.Equal(t, 3, strings.Count(pdf, "/Subtype /Type0"))
+}
+
+func TestFont(t *testing.T) {
+	f, err := loadFont("DejaVuSans.ttf")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, f.missing, f.glyph('Ж'))
+	assert.Equal(t, f.missing, f.glyph('\U0010FFFF'))
+	assert.InDelta(t, 2*f.width("a", 10), f.width("aa", 10), 1e-9)
+	assert.Greater(t, f.width("Ш", 10), f.width("i", 10))
+	assert.False(t, f.fixedPitch())
+
+	sub, err := f.subset(map[uint16]bool{f.glyph('Ж'): true, f.glyph('é'): true})
+	require.NoError(t, err)
+	assert.Zero(t, checksum(sub)-0xB1B0AFBA)
+
+	// Subset has no character map, text refers to glyphs by ids.
+	tables, err := parseTables(sub)
+	require.NoError(t, err)
+	parsed := &font{tables: tables}
+	assert.Equal(t, f.tables["hmtx"], tables["hmtx"])
+
+	data, err := parsed.glyphData(f.glyph('Ж'))
+	require.NoError(t, err)
+	assert.NotEmpty(t, data)
+	data, err = parsed.glyphData(f.glyph('a'))
+	require.NoError(t, err)
+	assert.Empty(t, data)
+	assert.Less(t, len(tables["glyf"]), len(f.tables["glyf"])/100)
+}
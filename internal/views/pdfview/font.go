@@ -0,0 +1,462 @@
+package pdfview
+
+import (
+	"embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+//go:embed fonts/*.ttf
+var fontFiles embed.FS
+
+var errBadFont = errors.New("malformed TrueType font")
+
+// font is TrueType font parsed enough to measure text and embed subset of its glyphs into PDF.
+type font struct {
+	name   string
+	tables map[string][]byte
+
+	unitsPerEm int
+	ascent     int
+	descent    int
+	bbox       [4]int
+	numGlyphs  int
+	// advances are widths of glyphs in font units by glyph id.
+	advances []int
+	glyphs   map[rune]uint16
+	// missing is glyph drawn for characters absent in font.
+	missing uint16
+}
+
+// loadFont parses embedded font file.
+func loadFont(file string) (*font, error) {
+	data, err := fontFiles.ReadFile("fonts/" + file)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := parseFont(data)
+	if err != nil {
+		return nil, fmt.Errorf("font %s: %w", file, err)
+	}
+	f.name = file[:len(file)-len(".ttf")]
+
+	return f, nil
+}
+
+func parseFont(data []byte) (*font, error) {
+	tables, err := parseTables(data)
+	if err != nil {
+		return nil, err
+	}
+	f := &font{tables: tables}
+
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "cmap", "loca", "glyf"} {
+		if _, ok := f.tables[tag]; !ok {
+			return nil, fmt.Errorf("%w: no %s table", errBadFont, tag)
+		}
+	}
+
+	head, hhea := f.tables["head"], f.tables["hhea"]
+	if len(head) < 54 || len(hhea) < 36 || len(f.tables["maxp"]) < 6 {
+		return nil, errBadFont
+	}
+	f.unitsPerEm = int(u16(head, 18))
+	for i := range f.bbox {
+		f.bbox[i] = int(int16(u16(head, 36+2*i)))
+	}
+	f.ascent = int(int16(u16(hhea, 4)))
+	f.descent = int(int16(u16(hhea, 6)))
+	f.numGlyphs = int(u16(f.tables["maxp"], 4))
+	if f.unitsPerEm == 0 {
+		return nil, errBadFont
+	}
+
+	numMetrics := int(u16(hhea, 34))
+	hmtx := f.tables["hmtx"]
+	if numMetrics == 0 || len(hmtx) < 4*numMetrics {
+		return nil, errBadFont
+	}
+	f.advances = make([]int, f.numGlyphs)
+	for g := range f.advances {
+		if g < numMetrics {
+			f.advances[g] = int(u16(hmtx, 4*g))
+		} else {
+			f.advances[g] = f.advances[numMetrics-1]
+		}
+	}
+
+	err = f.parseCmap()
+	if err != nil {
+		return nil, err
+	}
+	f.missing = f.glyphs['?']
+
+	return f, nil
+}
+
+// parseTables reads table directory of font file.
+func parseTables(data []byte) (map[string][]byte, error) {
+	if len(data) < 12 {
+		return nil, errBadFont
+	}
+
+	numTables := int(u16(data, 4))
+	if len(data) < 12+16*numTables {
+		return nil, errBadFont
+	}
+
+	tables := make(map[string][]byte, numTables)
+	for i := 0; i < numTables; i++ {
+		rec := data[12+16*i:]
+		off, length := u32(rec, 8), u32(rec, 12)
+		if uint64(off)+uint64(length) > uint64(len(data)) {
+			return nil, errBadFont
+		}
+		tables[string(rec[:4])] = data[off : off+length]
+	}
+
+	return tables, nil
+}
+
+// parseCmap reads mapping of characters to glyphs from Unicode subtable of format 12 or 4.
+func (f *font) parseCmap() error {
+	cmap := f.tables["cmap"]
+	if len(cmap) < 4 {
+		return errBadFont
+	}
+
+	var format4, format12 []byte
+	for i := 0; i < int(u16(cmap, 2)); i++ {
+		if len(cmap) < 4+8*i+8 {
+			return errBadFont
+		}
+		rec := cmap[4+8*i:]
+		platform, encoding, off := u16(rec, 0), u16(rec, 2), u32(rec, 4)
+		if int(off)+2 > len(cmap) {
+			return errBadFont
+		}
+		sub := cmap[off:]
+
+		unicode := platform == 0 || platform == 3 && (encoding == 1 || encoding == 10)
+		switch {
+		case !unicode:
+		case u16(sub, 0) == 4:
+			format4 = sub
+		case u16(sub, 0) == 12:
+			format12 = sub
+		}
+	}
+
+	f.glyphs = make(map[rune]uint16)
+	switch {
+	case format12 != nil:
+		return f.parseCmap12(format12)
+	case format4 != nil:
+		return f.parseCmap4(format4)
+	}
+
+	return fmt.Errorf("%w: no unicode cmap", errBadFont)
+}
+
+func (f *font) parseCmap4(sub []byte) error {
+	if len(sub) < 14 {
+		return errBadFont
+	}
+
+	segCount := int(u16(sub, 6) / 2)
+	ends, starts, deltas, offsets := 14, 16+2*segCount, 16+4*segCount, 16+6*segCount
+	if len(sub) < offsets+2*segCount {
+		return errBadFont
+	}
+
+	for i := 0; i < segCount; i++ {
+		end, start := rune(u16(sub, ends+2*i)), rune(u16(sub, starts+2*i))
+		delta, rangeOff := u16(sub, deltas+2*i), int(u16(sub, offsets+2*i))
+
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			g := uint16(c) + delta
+			if rangeOff != 0 {
+				at := offsets + 2*i + rangeOff + 2*int(c-start)
+				if at+2 > len(sub) {
+					return errBadFont
+				}
+				g = u16(sub, at)
+				if g != 0 {
+					g += delta
+				}
+			}
+
+			if g != 0 && int(g) < f.numGlyphs {
+				f.glyphs[c] = g
+			}
+		}
+	}
+
+	return nil
+}
+
+func (f *font) parseCmap12(sub []byte) error {
+	if len(sub) < 16 {
+		return errBadFont
+	}
+
+	numGroups := int(u32(sub, 12))
+	if len(sub) < 16+12*numGroups {
+		return errBadFont
+	}
+
+	for i := 0; i < numGroups; i++ {
+		group := sub[16+12*i:]
+		start, end, g := rune(u32(group, 0)), rune(u32(group, 4)), int(u32(group, 8))
+		for c := start; c <= end && g < f.numGlyphs; c++ {
+			if g != 0 {
+				f.glyphs[c] = uint16(g)
+			}
+			g++
+		}
+	}
+
+	return nil
+}
+
+// glyph returns glyph of character c.
+func (f *font) glyph(c rune) uint16 {
+	if g, ok := f.glyphs[c]; ok {
+		return g
+	}
+
+	return f.missing
+}
+
+// width returns width of text in points for font size.
+func (f *font) width(text string, size float64) float64 {
+	units := 0
+	for _, c := range text {
+		units += f.advances[f.glyph(c)]
+	}
+
+	return float64(units) * size / float64(f.unitsPerEm)
+}
+
+// scale converts font units into PDF glyph space units, which are 1/1000 of font size.
+func (f *font) scale(units int) int {
+	return units * 1000 / f.unitsPerEm
+}
+
+// fixedPitch reports whether all glyphs of font have the same width.
+func (f *font) fixedPitch() bool {
+	post := f.tables["post"]
+	return len(post) >= 16 && u32(post, 12) != 0
+}
+
+// glyphData returns outline of glyph g from glyf table.
+func (f *font) glyphData(g uint16) ([]byte, error) {
+	loca, glyf := f.tables["loca"], f.tables["glyf"]
+
+	var start, end int
+	if int16(u16(f.tables["head"], 50)) == 0 {
+		if len(loca) < 2*int(g)+4 {
+			return nil, errBadFont
+		}
+		start, end = 2*int(u16(loca, 2*int(g))), 2*int(u16(loca, 2*int(g)+2))
+	} else {
+		if len(loca) < 4*int(g)+8 {
+			return nil, errBadFont
+		}
+		start, end = int(u32(loca, 4*int(g))), int(u32(loca, 4*int(g)+4))
+	}
+
+	if start > end || end > len(glyf) {
+		return nil, errBadFont
+	}
+
+	return glyf[start:end], nil
+}
+
+// Flags of components of composite glyph.
+const (
+	argsAreWords    = 0x0001
+	haveScale       = 0x0008
+	moreComponents  = 0x0020
+	haveXYScale     = 0x0040
+	haveTwoByTwo    = 0x0080
+	compositeHeader = 10
+)
+
+// components returns glyphs composite glyph is made of.
+func components(data []byte) ([]uint16, error) {
+	if len(data) < compositeHeader || int16(u16(data, 0)) >= 0 {
+		return nil, nil
+	}
+
+	var res []uint16
+	for at := compositeHeader; ; {
+		if len(data) < at+4 {
+			return nil, errBadFont
+		}
+		flags := u16(data, at)
+		res = append(res, u16(data, at+2))
+
+		at += 4
+		if flags&argsAreWords != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+
+		if flags&moreComponents == 0 {
+			return res, nil
+		}
+	}
+}
+
+// subsetTables are tables copied into font subset, hinting tables included.
+var subsetTables = []string{"cvt ", "fpgm", "prep", "hhea", "hmtx", "maxp"}
+
+// subset returns font file with outlines of used glyphs only. Glyph ids are kept, so text
+// can be written with ids of the original font.
+func (f *font) subset(used map[uint16]bool) ([]byte, error) {
+	keep := make(map[uint16]bool)
+	// Glyph 0 is required, it is drawn for missing characters.
+	queue := []uint16{0}
+	for g := range used {
+		queue = append(queue, g)
+	}
+	for len(queue) > 0 {
+		g := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if keep[g] || int(g) >= f.numGlyphs {
+			continue
+		}
+		keep[g] = true
+
+		data, err := f.glyphData(g)
+		if err != nil {
+			return nil, err
+		}
+		parts, err := components(data)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, parts...)
+	}
+
+	var glyf []byte
+	loca := make([]byte, 4*(f.numGlyphs+1))
+	for g := 0; g < f.numGlyphs; g++ {
+		binary.BigEndian.PutUint32(loca[4*g:], uint32(len(glyf)))
+		if !keep[uint16(g)] {
+			continue
+		}
+
+		data, err := f.glyphData(uint16(g))
+		if err != nil {
+			return nil, err
+		}
+		glyf = append(glyf, data...)
+		for len(glyf)%4 != 0 {
+			glyf = append(glyf, 0)
+		}
+	}
+	binary.BigEndian.PutUint32(loca[4*f.numGlyphs:], uint32(len(glyf)))
+
+	head := append([]byte(nil), f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := map[string][]byte{"head": head, "loca": loca, "glyf": glyf}
+	for _, tag := range subsetTables {
+		if t, ok := f.tables[tag]; ok {
+			tables[tag] = t
+		}
+	}
+
+	res := writeFont(tables)
+
+	// Whole font checksum is stored in head, it is calculated with zero adjustment.
+	headAt := u32(res, 12+16*tableIndex(tables, "head")+8)
+	binary.BigEndian.PutUint32(res[headAt+8:], 0xB1B0AFBA-checksum(res))
+
+	return res, nil
+}
+
+// tableIndex returns position of table in sorted table directory.
+func tableIndex(tables map[string][]byte, tag string) int {
+	i := 0
+	for t := range tables {
+		if t < tag {
+			i++
+		}
+	}
+
+	return i
+}
+
+// writeFont assembles font file from tables.
+func writeFont(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for t := range tables {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= len(tags) {
+		entrySelector++
+	}
+	searchRange := 16 << entrySelector
+
+	res := make([]byte, 12+16*len(tags))
+	binary.BigEndian.PutUint32(res, 0x00010000)
+	binary.BigEndian.PutUint16(res[4:], uint16(len(tags)))
+	binary.BigEndian.PutUint16(res[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(res[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(res[10:], uint16(16*len(tags)-searchRange))
+
+	for i, tag := range tags {
+		t := tables[tag]
+		rec := res[12+16*i:]
+		copy(rec, tag)
+		binary.BigEndian.PutUint32(rec[4:], checksum(t))
+		binary.BigEndian.PutUint32(rec[8:], uint32(len(res)))
+		binary.BigEndian.PutUint32(rec[12:], uint32(len(t)))
+
+		res = append(res, t...)
+		for len(res)%4 != 0 {
+			res = append(res, 0)
+		}
+	}
+
+	return res
+}
+
+// checksum is sum of data as big-endian 32-bit words, data is padded with zeros.
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+
+	return sum
+}
+
+func u16(b []byte, at int) uint16 {
+	return binary.BigEndian.Uint16(b[at:])
+}
+
+func u32(b []byte, at int) uint32 {
+	return binary.BigEndian.Uint32(b[at:])
+}
@@ -0,0 +1,204 @@
+package pdfview
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// objWriter writes numbered PDF objects and cross-reference table of them. Object numbers are allocated
+// before objects are written, so objects can refer to each other in any order.
+type objWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func newObjWriter() *objWriter {
+	w := &objWriter{}
+	// Binary comment marks file as binary for transfer programs.
+	w.buf.WriteString("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n")
+
+	return w
+}
+
+// alloc returns number of new object.
+func (w *objWriter) alloc() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+// object writes object with number id.
+func (w *objWriter) object(id int, body string) {
+	w.offsets[id-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+// stream writes stream object compressed with Flate. Entries of dict are written in stream dictionary
+// along with length and filter.
+func (w *objWriter) stream(id int, dict string, data []byte) error {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	_, err := zw.Write(data)
+	if err != nil {
+		return err
+	}
+	err = zw.Close()
+	if err != nil {
+		return err
+	}
+
+	if dict != "" {
+		dict += " "
+	}
+
+	w.offsets[id-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< %s/Length %d /Filter /FlateDecode >>\nstream\n", id, dict, z.Len())
+	w.buf.Write(z.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+
+	return nil
+}
+
+// finish writes cross-reference table and trailer with catalog root and document info into out.
+func (w *objWriter) finish(out io.Writer, root int, info int) error {
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, off := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets)+1, root, info, xref)
+
+	_, err := w.buf.WriteTo(out)
+	return err
+}
+
+// textString encodes s as PDF text string in UTF-16 for document info and outlines.
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteString(">")
+
+	return b.String()
+}
+
+// ref returns reference to object id.
+func ref(id int) string {
+	return fmt.Sprintf("%d 0 R", id)
+}
+
+// embedFont writes font as composite font with subset of used glyphs and returns its object number.
+// Text is written with two-byte glyph ids, used maps them to characters for copying and search.
+func (w *objWriter) embedFont(f *font, used map[uint16]rune) (int, error) {
+	glyphs := make(map[uint16]bool, len(used))
+	for g := range used {
+		glyphs[g] = true
+	}
+
+	file, err := f.subset(glyphs)
+	if err != nil {
+		return 0, err
+	}
+
+	fontID, cidID, descID, fileID, unicodeID := w.alloc(), w.alloc(), w.alloc(), w.alloc(), w.alloc()
+	name := subsetTag(used) + "+" + f.name
+
+	w.object(fontID, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+		"/DescendantFonts [%s] /ToUnicode %s >>", name, ref(cidID), ref(unicodeID)))
+
+	gids := sortedGlyphs(used)
+	var widths strings.Builder
+	for _, g := range gids {
+		fmt.Fprintf(&widths, "%d [%d] ", g, f.scale(f.advances[g]))
+	}
+	w.object(cidID, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+		"/FontDescriptor %s /DW %d /W [%s] /CIDToGIDMap /Identity >>",
+		name, ref(descID), f.scale(f.advances[0]), widths.String()))
+
+	flags := 32
+	if f.fixedPitch() {
+		flags |= 1
+	}
+	w.object(descID, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags %d /FontBBox [%d %d %d %d] "+
+		"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %s >>",
+		name, flags, f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+		f.scale(f.ascent), f.scale(f.descent), f.scale(f.ascent), ref(fileID)))
+
+	err = w.stream(fileID, fmt.Sprintf("/Length1 %d", len(file)), file)
+	if err != nil {
+		return 0, err
+	}
+
+	err = w.stream(unicodeID, "", toUnicode(gids, used))
+	if err != nil {
+		return 0, err
+	}
+
+	return fontID, nil
+}
+
+// subsetTag returns six uppercase letters PDF requires as prefix of subset font name, it is derived from
+// glyphs of subset.
+func subsetTag(used map[uint16]rune) string {
+	h := uint32(2166136261)
+	for _, g := range sortedGlyphs(used) {
+		h = (h ^ uint32(g)) * 16777619
+	}
+
+	tag := make([]byte, 6)
+	for i := range tag {
+		tag[i] = byte('A' + h%26)
+		h /= 26
+	}
+
+	return string(tag)
+}
+
+// toUnicode returns CMap mapping glyph ids to characters.
+func toUnicode(gids []uint16, used map[uint16]rune) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	// Mappings are written in blocks, one block can't have more than 100 of them.
+	for start := 0; start < len(gids); start += 100 {
+		end := start + 100
+		if end > len(gids) {
+			end = len(gids)
+		}
+
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, g := range gids[start:end] {
+			fmt.Fprintf(&b, "<%04X> <", g)
+			for _, u := range utf16.Encode([]rune{used[g]}) {
+				fmt.Fprintf(&b, "%04X", u)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+
+	return b.Bytes()
+}
+
+func sortedGlyphs(used map[uint16]rune) []uint16 {
+	res := make([]uint16, 0, len(used))
+	for g := range used {
+		res = append(res, g)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+
+	return res
+}
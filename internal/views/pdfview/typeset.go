@@ -0,0 +1,244 @@
+package pdfview
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page size and margins in points.
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	margin       = 56.69
+	footerY      = 28.35
+	contentWidth = pageWidth - 2*margin
+)
+
+// Faces of fonts used in document.
+const (
+	regular = iota
+	bold
+	mono
+	faces
+)
+
+var fontFileNames = [faces]string{"DejaVuSans.ttf", "DejaVuSans-Bold.ttf", "DejaVuSansMono.ttf"}
+
+// style is font face, size and line height of text.
+type style struct {
+	face    int
+	size    float64
+	leading float64
+}
+
+// page is content of one page being typeset.
+type page struct {
+	content bytes.Buffer
+	links   []link
+	// id is object number of page, it is set when document is written.
+	id int
+}
+
+// anchor is position in document links and outline entries point to.
+type anchor struct {
+	page *page
+	y    float64
+}
+
+// link is clickable area of page leading to anchor.
+type link struct {
+	x, y, w, h float64
+	to         anchor
+}
+
+// typesetter breaks text into lines and lines into pages.
+type typesetter struct {
+	fonts *[faces]*font
+	// used are glyphs drawn with each face mapped to characters they represent.
+	used  [faces]map[uint16]rune
+	pages []*page
+	// y is top of free space on the last page.
+	y float64
+}
+
+func newTypesetter(fonts *[faces]*font) *typesetter {
+	t := &typesetter{fonts: fonts}
+	for i := range t.used {
+		t.used[i] = make(map[uint16]rune)
+	}
+
+	return t
+}
+
+func (t *typesetter) newPage() {
+	t.pages = append(t.pages, &page{})
+	t.y = pageHeight - margin
+}
+
+func (t *typesetter) page() *page {
+	if len(t.pages) == 0 {
+		t.newPage()
+	}
+
+	return t.pages[len(t.pages)-1]
+}
+
+// anchor returns current position.
+func (t *typesetter) anchor() anchor {
+	return anchor{page: t.page(), y: t.y}
+}
+
+// ensure starts new page if less than h points are left on the current one.
+func (t *typesetter) ensure(h float64) {
+	if len(t.pages) == 0 || t.y-h < margin {
+		t.newPage()
+	}
+}
+
+// space adds vertical gap unless the current page is empty.
+func (t *typesetter) space(h float64) {
+	if t.y < pageHeight-margin {
+		t.y -= h
+	}
+}
+
+// draw writes text on page p with baseline at x, y.
+func (t *typesetter) draw(p *page, s style, x, y float64, text string) {
+	f := t.fonts[s.face]
+
+	var hex strings.Builder
+	for _, c := range text {
+		g, ok := f.glyphs[c]
+		if !ok {
+			g, c = f.missing, '?'
+		}
+		if _, ok := t.used[s.face][g]; !ok {
+			t.used[s.face][g] = c
+		}
+		fmt.Fprintf(&hex, "%04X", g)
+	}
+
+	fmt.Fprintf(&p.content, "BT /F%d %.2f Tf 1 0 0 1 %.2f %.2f Tm <%s> Tj ET\n",
+		s.face, s.size, x, y, hex.String())
+}
+
+// fill draws gray rectangle.
+func (t *typesetter) fill(gray, x, y, w, h float64) {
+	fmt.Fprintf(&t.page().content, "%.2f g %.2f %.2f %.2f %.2f re f 0 g\n", gray, x, y, w, h)
+}
+
+// baseline returns baseline of line of style placed at top of free space, text is centered in line height.
+func (t *typesetter) baseline(s style) float64 {
+	f := t.fonts[s.face]
+	ascent := float64(f.ascent) * s.size / float64(f.unitsPerEm)
+	height := float64(f.ascent-f.descent) * s.size / float64(f.unitsPerEm)
+
+	return t.y - (s.leading-height)/2 - ascent
+}
+
+// line places one line of text at left of free space.
+func (t *typesetter) line(s style, indent float64, text string) {
+	t.ensure(s.leading)
+	t.draw(t.page(), s, margin+indent, t.baseline(s), text)
+	t.y -= s.leading
+}
+
+// paragraph places text wrapped by words into lines, line breaks of text are kept.
+func (t *typesetter) paragraph(s style, text string) {
+	for _, l := range t.wrapWords(s, contentWidth, text) {
+		t.line(s, 0, l)
+	}
+}
+
+// code places text with kept spaces on gray background, long lines are wrapped by characters.
+func (t *typesetter) code(s style, text string) {
+	const pad = 4
+
+	text = strings.TrimRight(strings.ReplaceAll(clean(text), "\t", "    "), "\n")
+	lines := make([]string, 0)
+	for _, l := range strings.Split(text, "\n") {
+		lines = append(lines, t.wrapChars(s, contentWidth-2*pad, l)...)
+	}
+
+	t.ensure(s.leading + pad)
+	t.fill(0.94, margin, t.y-pad, contentWidth, pad)
+	t.y -= pad
+	for _, l := range lines {
+		t.ensure(s.leading)
+		t.fill(0.94, margin, t.y-s.leading, contentWidth, s.leading)
+		t.draw(t.page(), s, margin+pad, t.baseline(s), l)
+		t.y -= s.leading
+	}
+	t.fill(0.94, margin, t.y-pad, contentWidth, pad)
+	t.y -= pad
+}
+
+// wrapWords splits text into lines not wider than width breaking them between words. Words longer
+// than line are broken by characters.
+func (t *typesetter) wrapWords(s style, width float64, text string) []string {
+	f := t.fonts[s.face]
+
+	res := make([]string, 0)
+	for _, para := range strings.Split(clean(text), "\n") {
+		cur := ""
+		for _, word := range strings.Fields(para) {
+			next := word
+			if cur != "" {
+				next = cur + " " + word
+			}
+			if f.width(next, s.size) <= width {
+				cur = next
+				continue
+			}
+
+			if cur != "" {
+				res = append(res, cur)
+			}
+			parts := t.wrapChars(s, width, word)
+			res = append(res, parts[:len(parts)-1]...)
+			cur = parts[len(parts)-1]
+		}
+		res = append(res, cur)
+	}
+
+	return res
+}
+
+// wrapChars splits text into lines not wider than width breaking them at any character.
+func (t *typesetter) wrapChars(s style, width float64, text string) []string {
+	f := t.fonts[s.face]
+
+	res := make([]string, 0)
+	start, w := 0, 0.0
+	for i, c := range text {
+		cw := f.width(string(c), s.size)
+		if w+cw > width && i > start {
+			res = append(res, text[start:i])
+			start, w = i, 0
+		}
+		w += cw
+	}
+
+	return append(res, text[start:])
+}
+
+// truncate shortens text with ellipsis to fit width.
+func (t *typesetter) truncate(s style, width float64, text string) string {
+	f := t.fonts[s.face]
+	if f.width(text, s.size) <= width {
+		return text
+	}
+
+	runes := []rune(text)
+	for len(runes) > 0 && f.width(string(runes)+"…", s.size) > width {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + "…"
+}
+
+// clean normalizes line breaks of text entered in browsers.
+func clean(text string) string {
+	return strings.ReplaceAll(text, "\r\n", "\n")
+}
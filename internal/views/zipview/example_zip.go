@@ -0,0 +1,37 @@
+package zipview
+
+import (
+	"archive/zip"
+	"documentation-mini-app/internal/domain/example"
+	"io"
+)
+
+// ExampleToWriter writes all example files into w as zip archive. Names of files are checked before
+// anything is written, so archive never contains paths outside of directory it is unpacked to.
+func ExampleToWriter(w io.Writer, exa *example.Example) error {
+	files := exa.AllFiles()
+	names := make([]string, len(files))
+	for i, f := range files {
+		var err error
+		names[i], err = example.CleanFileName(f.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	zw := zip.NewWriter(w)
+
+	for i, f := range files {
+		fw, err := zw.Create(names[i])
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(fw, f.Code)
+		if err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
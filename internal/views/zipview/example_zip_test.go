@@ -0,0 +1,48 @@
+package zipview
+
+import (
+	"archive/zip"
+	"bytes"
+	"documentation-mini-app/internal/domain/example"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExampleToWriter(t *testing.T) {
+	exa := example.Example{Files: []example.File{
+		{Name: "go.mod", Code: "module x"},
+		{Name: "./cmd//main.go", Code: "package main"},
+	}}
+
+	var b bytes.Buffer
+	require.NoError(t, ExampleToWriter(&b, &exa))
+
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	require.NoError(t, err)
+	require.Len(t, zr.File, 2)
+
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"go.mod", "cmd/main.go"}, names)
+
+	rc, err := zr.File[1].Open()
+	require.NoError(t, err)
+	code, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "package main", string(code))
+}
+
+func TestExampleToWriter_UnsafeName(t *testing.T) {
+	for _, name := range []string{"../../x", "/etc/x", `..\x`} {
+		var b bytes.Buffer
+		exa := example.Example{Files: []example.File{{Name: "ok.go"}, {Name: name}}}
+
+		assert.ErrorIs(t, ExampleToWriter(&b, &exa), example.ErrInvalidFiles, name)
+		assert.Zero(t, b.Len(), name)
+	}
+}
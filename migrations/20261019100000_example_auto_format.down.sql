@@ -0,0 +1,2 @@
+alter table example
+    drop column auto_format;
@@ -0,0 +1,2 @@
+alter table example
+    add auto_format boolean default true not null;
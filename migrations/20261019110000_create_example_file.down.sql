@@ -0,0 +1 @@
+drop table example_file;
@@ -0,0 +1,18 @@
+create table example_file
+(
+    id                 serial
+        constraint example_file_pk
+            primary key,
+    example_id         integer           not null
+        constraint example_file_example_id_fk
+            references example,
+    name               text              not null,
+    highlight_language varchar,
+    code               text              not null,
+    position           integer default 0 not null,
+    constraint example_file_example_id_name_uq
+        unique (example_id, name)
+);
+
+alter table example_file
+    owner to university;
@@ -0,0 +1 @@
+drop table article_link;
@@ -0,0 +1,17 @@
+create table article_link
+(
+    article_id  integer           not null
+        constraint article_link_article_id_fk
+            references article,
+    target_doc  text default ''   not null,
+    target_name text              not null,
+    position    integer default 0 not null,
+    constraint article_link_pk
+        primary key (article_id, target_doc, target_name)
+);
+
+alter table article_link
+    owner to university;
+
+create index article_link_target_name_idx
+    on article_link (target_name);
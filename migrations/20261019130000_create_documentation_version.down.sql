@@ -0,0 +1 @@
+drop table documentation_version;
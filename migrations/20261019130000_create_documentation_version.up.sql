@@ -0,0 +1,17 @@
+create table documentation_version
+(
+    id               serial
+        constraint documentation_version_pk
+            primary key,
+    documentation_id integer                                not null
+        constraint documentation_version_documentation_id_fk
+            references documentation,
+    name             varchar                                not null,
+    snapshot         jsonb                                  not null,
+    created_at       timestamp with time zone default now() not null,
+    constraint documentation_version_documentation_id_name_uq
+        unique (documentation_id, name)
+);
+
+alter table documentation_version
+    owner to university;
@@ -0,0 +1,7 @@
+drop table revision;
+
+alter table example
+    drop column published;
+
+alter table article
+    drop column published;
@@ -0,0 +1,29 @@
+alter table article
+    add published boolean default true not null;
+
+alter table example
+    add published boolean default true not null;
+
+create table revision
+(
+    id          serial
+        constraint revision_pk
+            primary key,
+    entity_type varchar                                not null,
+    entity_id   integer                                not null,
+    content     jsonb                                  not null,
+    status      varchar                                not null,
+    author      text                                   not null,
+    reviewer    text default ''                        not null,
+    created_at  timestamp with time zone default now() not null,
+    updated_at  timestamp with time zone default now() not null
+);
+
+alter table revision
+    owner to university;
+
+create index revision_entity_idx
+    on revision (entity_type, entity_id);
+
+create index revision_status_idx
+    on revision (status);
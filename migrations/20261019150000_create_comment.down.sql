@@ -0,0 +1,3 @@
+drop table comment;
+
+drop table comment_thread;
@@ -0,0 +1,41 @@
+create table comment_thread
+(
+    id          serial
+        constraint comment_thread_pk
+            primary key,
+    article_id  integer                                not null
+        constraint comment_thread_article_id_fk
+            references article,
+    example_id  integer
+        constraint comment_thread_example_id_fk
+            references example,
+    resolved    boolean                  default false not null,
+    resolved_by text                     default ''    not null,
+    author      text                                   not null,
+    created_at  timestamp with time zone default now() not null
+);
+
+alter table comment_thread
+    owner to university;
+
+create index comment_thread_article_id_idx
+    on comment_thread (article_id);
+
+create table comment
+(
+    id         serial
+        constraint comment_pk
+            primary key,
+    thread_id  integer                                not null
+        constraint comment_thread_id_fk
+            references comment_thread,
+    author     text                                   not null,
+    body       text                                   not null,
+    created_at timestamp with time zone default now() not null
+);
+
+alter table comment
+    owner to university;
+
+create index comment_thread_id_idx
+    on comment (thread_id);
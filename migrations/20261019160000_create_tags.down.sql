@@ -0,0 +1,3 @@
+drop table example_tags;
+
+drop table article_tags;
@@ -0,0 +1,31 @@
+create table article_tags
+(
+    article_id integer not null
+        constraint article_tags_article_id_fk
+            references article,
+    tag        text    not null,
+    constraint article_tags_pk
+        primary key (article_id, tag)
+);
+
+alter table article_tags
+    owner to university;
+
+create index article_tags_tag_idx
+    on article_tags (tag);
+
+create table example_tags
+(
+    example_id integer not null
+        constraint example_tags_example_id_fk
+            references example,
+    tag        text    not null,
+    constraint example_tags_pk
+        primary key (example_id, tag)
+);
+
+alter table example_tags
+    owner to university;
+
+create index example_tags_tag_idx
+    on example_tags (tag);
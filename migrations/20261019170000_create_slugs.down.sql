@@ -0,0 +1,7 @@
+drop table slug_redirect;
+
+alter table article
+    drop column slug;
+
+alter table documentation
+    drop column slug;
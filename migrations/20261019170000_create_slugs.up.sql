@@ -0,0 +1,23 @@
+alter table documentation
+    add slug text;
+
+create unique index documentation_slug_uindex
+    on documentation (slug);
+
+alter table article
+    add slug text;
+
+create unique index article_slug_uindex
+    on article (slug);
+
+create table slug_redirect
+(
+    entity    varchar not null,
+    slug      text    not null,
+    entity_id integer not null,
+    constraint slug_redirect_pk
+        primary key (entity, slug)
+);
+
+alter table slug_redirect
+    owner to university;
@@ -0,0 +1,8 @@
+alter table example
+    drop column deleted_at;
+
+alter table article
+    drop column deleted_at;
+
+alter table documentation
+    drop column deleted_at;
@@ -0,0 +1,8 @@
+alter table documentation
+    add deleted_at timestamp with time zone;
+
+alter table article
+    add deleted_at timestamp with time zone;
+
+alter table example
+    add deleted_at timestamp with time zone;
@@ -0,0 +1,28 @@
+create table audit_log
+(
+    id          bigserial                              not null,
+    created_at  timestamp with time zone default now() not null,
+    actor       text                                   not null,
+    action      varchar                                not null,
+    entity_type varchar                                not null,
+    entity_id   integer                                not null,
+    before      jsonb,
+    after       jsonb,
+    request_id  text                                   not null,
+    ip          text                                   not null,
+    constraint audit_log_pk
+        primary key (id)
+);
+
+create index audit_log_entity_index
+    on audit_log (entity_type, entity_id);
+
+create index audit_log_created_at_index
+    on audit_log (created_at);
+
+create rule audit_log_no_update as on update to audit_log do instead nothing;
+
+create rule audit_log_no_delete as on delete to audit_log do instead nothing;
+
+alter table audit_log
+    owner to university;
@@ -0,0 +1,3 @@
+drop table webhook_delivery;
+
+drop table webhook;
@@ -0,0 +1,49 @@
+create table webhook
+(
+    id               serial
+        constraint webhook_pk
+            primary key,
+    documentation_id integer                                not null
+        constraint webhook_documentation_id_fk
+            references documentation,
+    url              text                                   not null,
+    secret           text                                   not null,
+    events           text[]                                 not null,
+    created_at       timestamp with time zone default now() not null
+);
+
+alter table webhook
+    owner to university;
+
+create index webhook_documentation_id_idx
+    on webhook (documentation_id);
+
+create table webhook_delivery
+(
+    id              bigserial
+        constraint webhook_delivery_pk
+            primary key,
+    webhook_id      integer                                not null
+        constraint webhook_delivery_webhook_id_fk
+            references webhook
+            on delete cascade,
+    event           text                                   not null,
+    payload         jsonb                                  not null,
+    status          varchar                                not null,
+    attempts        integer                  default 0     not null,
+    next_attempt_at timestamp with time zone default now() not null,
+    last_error      text                     default ''    not null,
+    response_code   integer                  default 0     not null,
+    created_at      timestamp with time zone default now() not null,
+    delivered_at    timestamp with time zone
+);
+
+alter table webhook_delivery
+    owner to university;
+
+create index webhook_delivery_webhook_id_idx
+    on webhook_delivery (webhook_id);
+
+create index webhook_delivery_pending_idx
+    on webhook_delivery (next_attempt_at)
+    where status = 'pending';
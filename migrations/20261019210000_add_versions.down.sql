@@ -0,0 +1,8 @@
+alter table example
+    drop column version;
+
+alter table article
+    drop column version;
+
+alter table documentation
+    drop column version;
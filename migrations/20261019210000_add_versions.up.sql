@@ -0,0 +1,8 @@
+alter table documentation
+    add version integer default 1 not null;
+
+alter table article
+    add version integer default 1 not null;
+
+alter table example
+    add version integer default 1 not null;
@@ -0,0 +1,4 @@
+drop index example_code_hash_idx;
+
+alter table example
+    drop column code_hash;
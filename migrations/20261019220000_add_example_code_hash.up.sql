@@ -0,0 +1,5 @@
+alter table example
+    add code_hash text;
+
+create index example_code_hash_idx
+    on example (code_hash);
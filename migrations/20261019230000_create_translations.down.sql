@@ -0,0 +1,3 @@
+drop table example_translation;
+
+drop table article_translation;
@@ -0,0 +1,32 @@
+create table article_translation
+(
+    article_id     integer                                not null
+        constraint article_translation_article_id_fk
+            references article,
+    locale         text                                   not null,
+    name           text                                   not null,
+    description    text                                   not null,
+    source_version integer                                not null,
+    author         text                                   not null,
+    updated_at     timestamp with time zone default now() not null,
+    constraint article_translation_pk
+        primary key (article_id, locale)
+);
+
+alter table article_translation
+    owner to university;
+
+create table example_translation
+(
+    example_id     integer not null
+        constraint example_translation_example_id_fk
+            references example,
+    locale         text    not null,
+    description    text    not null,
+    source_version integer not null,
+    constraint example_translation_pk
+        primary key (example_id, locale)
+);
+
+alter table example_translation
+    owner to university;
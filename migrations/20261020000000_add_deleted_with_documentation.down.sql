@@ -0,0 +1,5 @@
+alter table example
+    drop column deleted_with_documentation_id;
+
+alter table article
+    drop column deleted_with_documentation_id;
@@ -0,0 +1,5 @@
+alter table article
+    add deleted_with_documentation_id integer references documentation (id) on delete set null;
+
+alter table example
+    add deleted_with_documentation_id integer references documentation (id) on delete set null;
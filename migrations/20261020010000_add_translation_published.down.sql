@@ -0,0 +1,2 @@
+alter table article_translation
+    drop column published;
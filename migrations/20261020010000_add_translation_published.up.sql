@@ -0,0 +1,6 @@
+-- Existing translations were visible to readers, new ones wait for publication.
+alter table article_translation
+    add published boolean default true not null;
+
+alter table article_translation
+    alter column published set default false;
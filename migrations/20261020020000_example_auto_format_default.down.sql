@@ -0,0 +1,2 @@
+alter table example
+    alter column auto_format set default true;
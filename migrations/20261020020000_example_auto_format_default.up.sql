@@ -0,0 +1,3 @@
+-- Code of examples is formatted only when the writer asks for it.
+alter table example
+    alter column auto_format set default false;
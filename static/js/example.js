@@ -0,0 +1,6 @@
+// showFile shows code of file idx of example and hides its other files.
+function showFile(exaID, idx) {
+    document.querySelectorAll('[id^="file-' + exaID + '-"]').forEach(function (el) {
+        el.hidden = el.id !== "file-" + exaID + "-" + idx;
+    });
+}
@@ -0,0 +1,17 @@
+// suggestTags fills datalist tag-list with known tags starting with the last tag typed in input.
+function suggestTags(input) {
+    const parts = input.value.split(",");
+    const last = parts.pop().trim().toLowerCase();
+    const head = parts.map(p => p.trim()).filter(p => p !== "").join(", ");
+    fetch("/tags?format=json&prefix=" + encodeURIComponent(last))
+        .then(resp => resp.json())
+        .then(tags => {
+            const list = document.getElementById("tag-list");
+            list.innerHTML = "";
+            for (const t of tags) {
+                const opt = document.createElement("option");
+                opt.value = head === "" ? t.name : head + ", " + t.name;
+                list.appendChild(opt);
+            }
+        });
+}
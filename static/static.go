@@ -0,0 +1,16 @@
+// Package static contains own and vendored CSS and JS served under /static.
+package static
+
+import "embed"
+
+// Vendored assets are fetched by go generate, versions are pinned in URLs.
+//go:generate curl -fsSL -o vendor/sakura/sakura.css https://unpkg.com/sakura.css@1.4.1/css/sakura.css
+//go:generate curl -fsSL -o vendor/sakura/sakura-dark.css https://unpkg.com/sakura.css@1.4.1/css/sakura-dark.css
+//go:generate curl -fsSL -o vendor/highlight/highlight.min.js https://cdnjs.cloudflare.com/ajax/libs/highlight.js/11.9.0/highlight.min.js
+//go:generate curl -fsSL -o vendor/highlight/languages/go.min.js https://cdnjs.cloudflare.com/ajax/libs/highlight.js/11.9.0/languages/go.min.js
+//go:generate curl -fsSL -o vendor/highlight/styles/default.min.css https://cdnjs.cloudflare.com/ajax/libs/highlight.js/11.9.0/styles/default.min.css
+
+// FS holds assets, paths are relative to this directory, e.g. vendor/sakura/sakura.css.
+//
+//go:embed vendor js
+var FS embed.FS
@@ -0,0 +1,9 @@
+// Package templates contains HTML templates of pages.
+package templates
+
+import "embed"
+
+// FS holds templates, paths are relative to this directory, e.g. docs/get_doc.html.
+//
+//go:embed *.html */*.html
+var FS embed.FS